@@ -0,0 +1,184 @@
+// clock.go
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tickInterval is how often a running clock is updated.
+const tickInterval = 100 * time.Millisecond
+
+// timeControl describes how much thinking time each player gets. The zero
+// value means the game is untimed.
+type timeControl struct {
+	PerMove   time.Duration // Time allowed for each move, 0 for none
+	PerGame   time.Duration // Time allowed for the whole game, 0 for none
+	Increment time.Duration // Added to the game clock after each move
+}
+
+// timePresets lists the time controls cycled through before a game starts.
+var timePresets = []timeControl{
+	{},
+	{PerMove: 30 * time.Second},
+	{PerMove: 10 * time.Second},
+	{PerGame: time.Minute},
+	{PerGame: 2 * time.Minute, Increment: 5 * time.Second},
+	{PerGame: 5 * time.Minute, Increment: 3 * time.Second},
+}
+
+// enabled reports whether the time control limits the players at all.
+func (tc timeControl) enabled() bool {
+	return tc.PerMove > 0 || tc.PerGame > 0
+}
+
+// String describes the time control, e.g. "2m0s + 5s".
+func (tc timeControl) String() string {
+	switch {
+	case !tc.enabled():
+		return "untimed"
+	case tc.PerGame > 0 && tc.PerMove > 0:
+		return fmt.Sprintf("%s per game, %s per move", tc.PerGame, tc.PerMove)
+	case tc.PerGame > 0 && tc.Increment > 0:
+		return fmt.Sprintf("%s + %s", tc.PerGame, tc.Increment)
+	case tc.PerGame > 0:
+		return fmt.Sprintf("%s per game", tc.PerGame)
+	default:
+		return fmt.Sprintf("%s per move", tc.PerMove)
+	}
+}
+
+// nextTimePreset returns the preset that follows tc in timePresets.
+func nextTimePreset(tc timeControl) timeControl {
+	for i, p := range timePresets {
+		if p == tc {
+			return timePresets[(i+1)%len(timePresets)]
+		}
+	}
+	return timePresets[0]
+}
+
+// clockIDs hands out a fresh id each time a clock starts, so ticks from an
+// earlier game are recognised and dropped.
+var clockIDs atomic.Int64
+
+// tickMsg is sent every tickInterval while a clock is running.
+type tickMsg struct {
+	id int64
+	t  time.Time
+}
+
+// clock tracks the time left for X and O under a time control.
+type clock struct {
+	control timeControl
+	game    [2]time.Duration // Game time left for X and O
+	move    time.Duration    // Time left for the current move
+	running bool
+	last    time.Time // When the clock was last brought up to date
+	id      int64
+}
+
+// newClock returns a stopped clock with full time for both players.
+func newClock(tc timeControl) clock {
+	return clock{
+		control: tc,
+		game:    [2]time.Duration{tc.PerGame, tc.PerGame},
+		move:    tc.PerMove,
+	}
+}
+
+// start sets the clock running and returns the command for its first tick.
+// An untimed clock never runs.
+func (c *clock) start(now time.Time) tea.Cmd {
+	if !c.control.enabled() {
+		return nil
+	}
+	c.running = true
+	c.last = now
+	c.id = clockIDs.Add(1)
+	return c.tick()
+}
+
+// stop pauses the clock; pending ticks are ignored.
+func (c *clock) stop() {
+	c.running = false
+}
+
+// tick schedules the next tickMsg for this clock.
+func (c clock) tick() tea.Cmd {
+	id := c.id
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
+		return tickMsg{id: id, t: t}
+	})
+}
+
+// elapse charges the time since the last update to the given player and
+// reports whether they have run out of time.
+func (c *clock) elapse(player string, now time.Time) bool {
+	spent := now.Sub(c.last)
+	c.last = now
+	flagged := false
+
+	if c.control.PerGame > 0 {
+		i := clockIndex(player)
+		c.game[i] = max(c.game[i]-spent, 0)
+		flagged = c.game[i] == 0
+	}
+	if c.control.PerMove > 0 {
+		c.move = max(c.move-spent, 0)
+		flagged = flagged || c.move == 0
+	}
+	return flagged
+}
+
+// moved credits the increment to the player who just moved and restarts the
+// move timer for their opponent.
+func (c *clock) moved(player string) {
+	if c.control.PerGame > 0 {
+		c.game[clockIndex(player)] += c.control.Increment
+	}
+	c.move = c.control.PerMove
+}
+
+// clockIndex maps a player's marker to their slot in clock.game.
+func clockIndex(player string) int {
+	if player == "O" {
+		return 1
+	}
+	return 0
+}
+
+// formatClock renders time left as m:ss, with tenths in the last ten seconds.
+func formatClock(d time.Duration) string {
+	if d < 10*time.Second {
+		return fmt.Sprintf("%.1fs", d.Truncate(time.Second/10).Seconds())
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// viewClock renders both players' clocks, highlighting the one that is running.
+func viewClock(m model) string {
+	active := lipgloss.NewStyle().Foreground(m.theme.cursor).Bold(true)
+	show := func(player string, left time.Duration) string {
+		text := fmt.Sprintf("%s %s", m.sideName(player), formatClock(left))
+		if m.clock.running && m.player == player {
+			return active.Render(text)
+		}
+		return text
+	}
+
+	var parts []string
+	if m.timeControl.PerGame > 0 {
+		parts = append(parts, show("X", m.clock.game[0])+" • "+show("O", m.clock.game[1]))
+	}
+	if m.timeControl.PerMove > 0 {
+		parts = append(parts, "Move "+formatClock(m.clock.move))
+	}
+	return fmt.Sprintf("Clock (%s): %s", m.timeControl, strings.Join(parts, " | "))
+}
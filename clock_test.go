@@ -0,0 +1,113 @@
+// clock_test.go
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timedModel returns a model in the playing state with a running clock.
+func timedModel(tc timeControl) model {
+	m, _ := playingModel(config{timeControl: tc}).newGame()
+	return m
+}
+
+// tickAfter sends a tick for the model's clock d after its last update.
+func tickAfter(m model, d time.Duration) (model, tea.Cmd) {
+	updatedModel, cmd := m.Update(tickMsg{id: m.clock.id, t: m.clock.last.Add(d)})
+	return updatedModel.(model), cmd
+}
+
+// TestFormatClock checks the countdown formatting.
+func TestFormatClock(t *testing.T) {
+	testCases := map[time.Duration]string{
+		2 * time.Minute:         "2:00",
+		75 * time.Second:        "1:15",
+		10 * time.Second:        "0:10",
+		9970 * time.Millisecond: "9.9s",
+		0:                       "0.0s",
+	}
+	for d, want := range testCases {
+		if got := formatClock(d); got != want {
+			t.Errorf("formatClock(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// TestClockLossOnTime checks that running out of move time loses the game.
+func TestClockLossOnTime(t *testing.T) {
+	m := timedModel(timeControl{PerMove: time.Second})
+	if !m.clock.running {
+		t.Fatal("Expected the clock to be running")
+	}
+
+	m, cmd := tickAfter(m, 500*time.Millisecond)
+	if m.winner != "" || cmd == nil {
+		t.Fatalf("Expected the game to continue after 0.5s, winner %q", m.winner)
+	}
+
+	m, cmd = tickAfter(m, 600*time.Millisecond)
+	if m.flagged != "X" || m.winner != "O" {
+		t.Errorf("Expected X to lose on time, got flagged %q winner %q", m.flagged, m.winner)
+	}
+	if m.seats[1].score != 1 {
+		t.Errorf("Expected O's score to be 1, got %d", m.seats[1].score)
+	}
+	if cmd != nil || m.clock.running {
+		t.Error("Expected the clock to stop once the game is over")
+	}
+	if !contains(m.View(), "P1 ran out of time! P2 wins!") {
+		t.Errorf("View does not report the loss on time")
+	}
+}
+
+// TestClockIgnoresStaleTicks checks that ticks from a stopped clock do nothing.
+func TestClockIgnoresStaleTicks(t *testing.T) {
+	m := timedModel(timeControl{PerGame: time.Minute})
+	stale := m.clock.id
+
+	m, _ = m.newGame()
+	updatedModel, cmd := m.Update(tickMsg{id: stale, t: m.clock.last.Add(time.Hour)})
+	m = updatedModel.(model)
+	if m.winner != "" || cmd != nil {
+		t.Errorf("Expected a stale tick to be ignored, winner %q", m.winner)
+	}
+}
+
+// TestClockIncrement checks that the mover's game clock gains the increment.
+func TestClockIncrement(t *testing.T) {
+	m := timedModel(timeControl{PerGame: time.Minute, Increment: 5 * time.Second})
+	m, _ = tickAfter(m, 10*time.Second)
+	m.clock.last = time.Now()
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if left := m.clock.game[0]; left < 54*time.Second || left > 55*time.Second {
+		t.Errorf("Expected X to have about 55s left after the increment, got %s", left)
+	}
+	if m.player != "O" {
+		t.Errorf("Expected player to switch to 'O', got '%s'", m.player)
+	}
+}
+
+// TestUpdateTimeControlKey checks that the time control only changes before the first move.
+func TestUpdateTimeControlKey(t *testing.T) {
+	m := playingModel(config{})
+	press := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}
+
+	updatedModel, cmd := m.Update(press)
+	m = updatedModel.(model)
+	if m.timeControl != timePresets[1] || cmd == nil {
+		t.Fatalf("Expected time control %s with a running clock, got %s", timePresets[1], m.timeControl)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(press)
+	m = updatedModel.(model)
+	if m.timeControl != timePresets[1] {
+		t.Errorf("Expected time control to stay %s after a move, got %s", timePresets[1], m.timeControl)
+	}
+}
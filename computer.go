@@ -0,0 +1,75 @@
+// computer.go
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// computerDelay is how long the computer waits before moving, so its move
+// can be seen.
+const computerDelay = 400 * time.Millisecond
+
+// computerName is used for player 2 when the computer plays and no name was
+// entered.
+const computerName = "Computer"
+
+// computerMoveMsg tells the computer to make its move.
+type computerMoveMsg struct{}
+
+// hasComputer reports whether the computer can play the mode.
+func (g gameMode) hasComputer() bool {
+	return g == modeQubic || g == modeNotakto || g == modePuzzle
+}
+
+// computerPlays reports whether the computer takes player 2's seat: when
+// asked to, and always in puzzle mode, where it plays the defence.
+func (m model) computerPlays() bool {
+	return m.computer || m.mode == modePuzzle
+}
+
+// computerToMove reports whether it is the computer's turn. The computer
+// takes player 2's seat, and in a team game every second seat, whichever
+// marker the seat plays this game.
+func (m model) computerToMove() bool {
+	return m.computerPlays() && m.mode.hasComputer() && m.turn%2 == 1 && m.winner == "" && !m.isDraw
+}
+
+// computerTurn returns the command that makes the computer move, if it is
+// the computer's turn.
+func (m model) computerTurn() tea.Cmd {
+	if !m.computerToMove() {
+		return nil
+	}
+	return tea.Tick(computerDelay, func(time.Time) tea.Msg {
+		return computerMoveMsg{}
+	})
+}
+
+// chooseComputerMove moves the cursor to the computer's chosen cell and
+// reports whether it found a move.
+func (m *model) chooseComputerMove() bool {
+	switch m.mode {
+	case modeNotakto:
+		b, x, y, ok := notaktoMove(m.notakto)
+		if ok {
+			m.cursorX, m.cursorY = b*3+x, y
+		}
+		return ok
+	case modeQubic:
+		p, ok := qubicMove(m.qubic, m.player, m.misereRules())
+		if ok {
+			m.cursorX, m.cursorY, m.cursorZ = p.x, p.y, p.z
+		}
+		return ok
+	case modePuzzle:
+		x, y, ok := defence(m.grid, m.puzzleMovesLeft())
+		if ok {
+			m.cursorX, m.cursorY = x, y
+		}
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,236 @@
+// config.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// config holds the user settings loaded from the config file.
+type config struct {
+	// Keys overrides key bindings by action name, e.g. {"up": ["w"]}.
+	Keys map[string][]string `json:"keys"`
+	// Theme names the colour theme, e.g. "solarized".
+	Theme string `json:"theme"`
+	// Glyphs controls big block-art markers: "auto", "on" or "off".
+	Glyphs string `json:"glyphs"`
+	// Mode names the game mode, e.g. "ultimate".
+	Mode string `json:"mode"`
+	// Misere turns on misère play, where completing a line loses.
+	Misere bool `json:"misere"`
+	// Disappearing limits each player to three marks in classic mode,
+	// removing their oldest when they place a fourth.
+	Disappearing bool `json:"disappearing"`
+	// Boards sets the number of boards in notakto mode.
+	Boards int `json:"boards"`
+	// Players sets each player's symbol and colour, e.g.
+	// [{"symbol": "★", "color": "#ffaf00"}, {}, {"symbol": "♦"}]. Listing
+	// more than two players starts the setup screen with a row for each.
+	Players []playerConfig `json:"players"`
+	// FirstMove decides who starts each game: "player1" (the default),
+	// "alternate", "loser", "winner", "random" or "chosen" (the player
+	// picked on the setup screen, player 1 unless another is picked).
+	// Whoever starts plays X.
+	FirstMove string `json:"first_move"`
+	// Opening sets a fairness rule for the start of each game: "swap", lets
+	// O take over X's first move, and in Gomoku "swap2" and "balanced"
+	// (a random even position) are also available. "none" is the default.
+	Opening string `json:"opening"`
+	// Torus makes the classic board wrap round at the edges, so lines can
+	// run off one side and continue on the other.
+	Torus bool `json:"torus"`
+	// Shape cuts the classic board to "plus" or "diamond"; "square" keeps
+	// every cell.
+	Shape string `json:"shape"`
+	// Obstacles blocks this many random cells of the classic board.
+	Obstacles int `json:"obstacles"`
+	// Seed fixes the random obstacles, so every game has the same ones. With
+	// no seed they are new each game.
+	Seed uint64 `json:"seed"`
+	// Layout is a file drawing the classic board, one row per line with "."
+	// for an open cell and "#" for a blocked one.
+	Layout string `json:"layout"`
+	// Puzzles is a file of puzzles to play in puzzle mode after the built-in
+	// ones, in the format described in puzzles.txt.
+	Puzzles string `json:"puzzles"`
+	// Match plays the games as a match that ends when a side reaches a
+	// number of wins or after a number of games, e.g. {"first_to": 5} or
+	// {"best_of": 3}. Scores then count within the match.
+	Match matchConfig `json:"match"`
+	// Teams plays two teams of two: the players of each team share a marker
+	// and take their team's turns in rotation.
+	Teams bool `json:"teams"`
+	// Computer lets the computer play second in the modes it supports.
+	Computer bool `json:"computer"`
+	// GomokuRule names the Gomoku rule: "freestyle", "standard" or "renju".
+	GomokuRule string `json:"gomoku_rule"`
+	// Grid sizes the board in gravity mode, on a torus, and in classic games
+	// of three or four players, e.g. {"cols": 7, "rows": 6, "connect": 4}.
+	Grid gridConfig `json:"grid"`
+	// Time sets the default time control, e.g. {"game": "2m", "increment": "5s"}.
+	Time timeConfig `json:"time"`
+
+	timeControl timeControl // Parsed from Time by loadConfig
+	layout      [][]bool    // Read from the Layout file by loadConfig
+	puzzles     []puzzle    // Read from the Puzzles file by loadConfig
+	noColor     bool        // Set by NO_COLOR, which keeps the theme monochrome
+
+	stats     stats  // Results kept across sessions
+	statsPath string // Where stats are saved; none if empty
+}
+
+// timeConfig is the config file form of a timeControl, using Go duration
+// strings such as "30s" or "2m".
+type timeConfig struct {
+	Move      string `json:"move"`
+	Game      string `json:"game"`
+	Increment string `json:"increment"`
+}
+
+// parse converts the duration strings into a timeControl.
+func (tc timeConfig) parse() (timeControl, error) {
+	var c timeControl
+	fields := []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"move", tc.Move, &c.PerMove},
+		{"game", tc.Game, &c.PerGame},
+		{"increment", tc.Increment, &c.Increment},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(f.value)
+		if err != nil || d < 0 {
+			return c, fmt.Errorf("invalid %s time %q", f.name, f.value)
+		}
+		*f.dst = d
+	}
+	return c, nil
+}
+
+// defaultConfig returns the settings used when no config file is present.
+func defaultConfig() config {
+	return config{}
+}
+
+// defaultConfigPath returns the location of the config file in the user's
+// config directory, e.g. ~/.config/tictactoe/config.json.
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tictactoe", "config.json"), nil
+}
+
+// loadConfig reads the config file at path. An empty path means the default
+// location, which is allowed to be missing.
+func loadConfig(path string) (config, error) {
+	cfg := defaultConfig()
+
+	explicit := path != ""
+	if !explicit {
+		p, err := defaultConfigPath()
+		if err != nil {
+			return cfg, nil // No config directory, so nothing to load
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	// Validate the key overrides up front so a typo is reported on startup.
+	keys := defaultKeyMap()
+	if err := keys.applyOverrides(cfg.Keys); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := themeByName(cfg.Theme); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := validateGlyphMode(cfg.Glyphs); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := parseMode(cfg.Mode); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := validatePlayers(cfg.Players); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.checkModePlayers(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := validateNotaktoBoards(cfg.Boards); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := parseGomokuRule(cfg.GomokuRule); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := cfg.Grid.validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := parseFirstMovePolicy(cfg.FirstMove); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := cfg.Match.validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := parseOpeningRule(cfg.Opening); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if _, err := parseBoardShape(cfg.Shape); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	if cfg.Obstacles < 0 {
+		return cfg, fmt.Errorf("config %s: obstacles must not be negative, got %d", path, cfg.Obstacles)
+	}
+	if cfg.Layout != "" {
+		if cfg.layout, err = loadLayout(cfg.Layout); err != nil {
+			return cfg, fmt.Errorf("config %s: %w", path, err)
+		}
+		if err := cfg.checkLayout(); err != nil {
+			return cfg, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
+	if cfg.Puzzles != "" {
+		if cfg.puzzles, err = loadPuzzles(cfg.Puzzles); err != nil {
+			return cfg, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
+	if cfg.timeControl, err = cfg.Time.parse(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,99 @@
+// config_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeConfig writes a config file into a temp dir and returns its path.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+// TestLoadConfig covers valid, missing and malformed config files.
+func TestLoadConfig(t *testing.T) {
+	t.Run("Key overrides are loaded", func(t *testing.T) {
+		path := writeConfig(t, `{"keys": {"up": ["w"], "reset": ["x", "ctrl+x"]}}`)
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		if got := cfg.Keys["reset"]; len(got) != 2 || got[0] != "x" {
+			t.Errorf("Expected reset keys [x ctrl+x], got %v", got)
+		}
+	})
+
+	t.Run("Time control is parsed", func(t *testing.T) {
+		cfg, err := loadConfig(writeConfig(t, `{"time": {"game": "2m", "increment": "5s"}}`))
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		want := timeControl{PerGame: 2 * time.Minute, Increment: 5 * time.Second}
+		if cfg.timeControl != want {
+			t.Errorf("Expected time control %s, got %s", want, cfg.timeControl)
+		}
+	})
+
+	t.Run("Invalid duration is an error", func(t *testing.T) {
+		if _, err := loadConfig(writeConfig(t, `{"time": {"move": "soon"}}`)); err == nil {
+			t.Error("Expected an error for an invalid duration, got nil")
+		}
+	})
+
+	t.Run("Missing explicit file is an error", func(t *testing.T) {
+		if _, err := loadConfig(filepath.Join(t.TempDir(), "nope.json")); err == nil {
+			t.Error("Expected an error for a missing config file, got nil")
+		}
+	})
+
+	t.Run("Malformed JSON is an error", func(t *testing.T) {
+		if _, err := loadConfig(writeConfig(t, `{"keys": `)); err == nil {
+			t.Error("Expected an error for malformed JSON, got nil")
+		}
+	})
+
+	t.Run("Unknown theme is an error", func(t *testing.T) {
+		if _, err := loadConfig(writeConfig(t, `{"theme": "neon"}`)); err == nil {
+			t.Error("Expected an error for an unknown theme, got nil")
+		}
+	})
+
+	t.Run("Unknown binding is an error", func(t *testing.T) {
+		if _, err := loadConfig(writeConfig(t, `{"keys": {"jump": ["space"]}}`)); err == nil {
+			t.Error("Expected an error for an unknown binding, got nil")
+		}
+	})
+}
+
+// TestKeyOverrides checks that rebound keys drive the game and the help footer.
+func TestKeyOverrides(t *testing.T) {
+	cfg := config{Keys: map[string][]string{"down": {"s"}, "reset": {"x"}}}
+	m := playingModel(cfg)
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updatedModel.(model)
+	if m.cursorY != 1 {
+		t.Errorf("Expected cursorY to be 1 after pressing 's', got %d", m.cursorY)
+	}
+
+	// The old binding no longer moves the cursor.
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = updatedModel.(model)
+	if m.cursorY != 1 {
+		t.Errorf("Expected cursorY to stay 1 after pressing 'j', got %d", m.cursorY)
+	}
+
+	if !contains(m.View(), "x reset game") {
+		t.Errorf("Help footer does not show the rebound key 'x'")
+	}
+}
@@ -0,0 +1,91 @@
+// disappearing.go
+package main
+
+import "fmt"
+
+// Limits in disappearing play.
+const (
+	disappearingMarks     = 3  // Marks each player may have on the board
+	disappearingRepeats   = 3  // A position seen this many times is a draw
+	disappearingMoveLimit = 60 // A game still going after this many moves is a draw
+)
+
+// vanishingMarks tracks a classic game in which each player keeps at most
+// three marks: placing a fourth removes that player's oldest.
+type vanishingMarks struct {
+	placed    [2][]struct{ x, y int } // Each player's marks, oldest first
+	positions []string                // Every position reached, for the repetition rule
+}
+
+// disappearingRules reports whether marks disappear in this game. Only the
+// two-player classic 3x3 board has the option.
+func (m model) disappearingRules() bool {
+	return m.disappearing && m.mode == modeClassic && !m.classicGrid()
+}
+
+// vanishesNext reports whether the mark at (x, y) is the current player's
+// oldest, which goes when they next place a mark.
+func (m model) vanishesNext(x, y int) bool {
+	if !m.disappearingRules() || m.winner != "" || m.isDraw {
+		return false
+	}
+	placed := m.vanish.placed[clockIndex(m.player)]
+	return len(placed) == disappearingMarks && placed[0].x == x && placed[0].y == y
+}
+
+// placeDisappearing plays the current player's marker at the cursor,
+// removing their oldest mark if they already have three. With the board
+// never filling up, a repeated position or a long game is a draw instead.
+func (m *model) placeDisappearing() moveResult {
+	x, y := m.cursorX, m.cursorY
+	if m.board[y][x] != " " {
+		return moveIllegal
+	}
+
+	i := clockIndex(m.player)
+	placed := m.vanish.placed[i]
+	if len(placed) == disappearingMarks {
+		oldest := placed[0]
+		m.board[oldest.y][oldest.x] = " "
+		placed = placed[1:]
+	}
+	// Build a new slice so earlier copies of the model keep their own.
+	m.vanish.placed[i] = append(append([]struct{ x, y int }(nil), placed...), struct{ x, y int }{x, y})
+	m.board[y][x] = m.player
+
+	if won, cells := checkWinner(m.board, m.player); won {
+		m.winningCells = cells
+		return moveWon
+	}
+
+	// The order of each player's marks decides which go next, so it is part
+	// of the position.
+	position := fmt.Sprint(m.vanish.placed, opponent(m.player))
+	m.vanish.positions = append(m.vanish.positions[:len(m.vanish.positions):len(m.vanish.positions)], position)
+	seen := 0
+	for _, p := range m.vanish.positions {
+		if p == position {
+			seen++
+		}
+	}
+	if seen >= disappearingRepeats || m.moves+1 >= disappearingMoveLimit {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// disappearingHint tells the current player which of their marks goes next.
+func disappearingHint(m model) string {
+	if len(m.vanish.placed[clockIndex(m.player)]) < disappearingMarks {
+		return fmt.Sprintf("Each player keeps at most %d marks", disappearingMarks)
+	}
+	return "Your dimmed mark disappears when you place your next one"
+}
+
+// faded returns the theme with markers dimmed, for drawing the mark that
+// disappears next.
+func (t theme) faded() theme {
+	t.x = t.x.Faint(true)
+	t.o = t.o.Faint(true)
+	return t
+}
@@ -0,0 +1,78 @@
+// disappearing_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestDisappearingOldestMark checks that a fourth mark removes the oldest.
+func TestDisappearingOldestMark(t *testing.T) {
+	m := playingModel(config{Disappearing: true})
+	for _, p := range []struct{ x, y int }{{0, 0}, {1, 0}, {2, 1}, {0, 1}, {1, 2}, {2, 2}} {
+		m = placeAt(m, p.x, p.y)
+	}
+	if !m.vanishesNext(0, 0) || m.vanishesNext(1, 0) {
+		t.Fatalf("Expected X's mark at (0, 0) to vanish next")
+	}
+
+	m = placeAt(m, 2, 0)
+	if m.board[0][0] != " " || m.board[0][2] != "X" {
+		t.Errorf("Expected X's oldest mark to be removed")
+	}
+	if got := len(m.vanish.placed[0]); got != 3 {
+		t.Errorf("Expected X to keep 3 marks, got %d", got)
+	}
+	if !m.vanishesNext(1, 0) {
+		t.Errorf("Expected O's mark at (1, 0) to vanish next")
+	}
+	if !contains(m.View(), "(Disappearing)") {
+		t.Errorf("View does not show disappearing play")
+	}
+}
+
+// TestDisappearingRepetition checks that a thrice repeated position is a draw.
+func TestDisappearingRepetition(t *testing.T) {
+	m := playingModel(config{Disappearing: true})
+	// Each player cycles through four cells, moving their oldest mark back
+	// to the cell it left, so the position repeats every four rounds
+	// without a line being made.
+	xCells := []struct{ x, y int }{{0, 0}, {2, 0}, {1, 2}, {0, 1}}
+	oCells := []struct{ x, y int }{{1, 0}, {2, 1}, {2, 2}, {0, 2}}
+	for i := 0; i < 40 && !m.isDraw; i++ {
+		p := xCells[i/2%4]
+		if i%2 == 1 {
+			p = oCells[i/2%4]
+		}
+		m = placeAt(m, p.x, p.y)
+		if m.winner != "" || m.moves != i+1 {
+			t.Fatalf("Expected move %d to be made without a winner", i+1)
+		}
+	}
+	if !m.isDraw {
+		t.Fatalf("Expected a draw by repetition")
+	}
+	if !contains(m.View(), "The position repeated 3 times.") {
+		t.Errorf("View does not explain the draw")
+	}
+}
+
+// TestUpdateDisappearingToggle checks that the toggle only works in classic
+// mode before the first move.
+func TestUpdateDisappearingToggle(t *testing.T) {
+	m := playingModel(config{})
+	dKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}
+
+	updatedModel, _ := m.Update(dKey)
+	m = updatedModel.(model)
+	if !m.disappearingRules() || !m.cfg.Disappearing {
+		t.Fatalf("Expected disappearing marks to be on")
+	}
+
+	m = placeAt(m, 1, 1)
+	updatedModel, _ = m.Update(dKey)
+	if !updatedModel.(model).disappearing {
+		t.Errorf("Expected the toggle to be ignored after the first move")
+	}
+}
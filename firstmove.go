@@ -0,0 +1,135 @@
+// firstmove.go
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// firstMovePolicy decides which player starts each game. Whoever starts
+// plays X, and the other markers follow round the table in seat order.
+type firstMovePolicy int
+
+const (
+	firstMovePlayer1   firstMovePolicy = iota // Player 1 starts every game
+	firstMoveAlternate                        // The first move passes round the table each game
+	firstMoveLoser                            // The loser of the last game starts
+	firstMoveWinner                           // The winner of the last game starts
+	firstMoveRandom                           // A random player starts
+	firstMoveChosen                           // The player picked on the setup screen starts every game
+)
+
+// firstMovePolicyNames lists the name of each policy, indexed by
+// firstMovePolicy. These are the names accepted by the "first_move" setting
+// and the --first-move flag.
+var firstMovePolicyNames = []string{"player1", "alternate", "loser", "winner", "random", "chosen"}
+
+// firstMovePolicyTitles holds the description shown on the setup screen for
+// each policy.
+var firstMovePolicyTitles = []string{"player 1 every game", "alternate each game", "loser of the last game", "winner of the last game", "random", "chosen at setup"}
+
+// String returns the policy's name.
+func (p firstMovePolicy) String() string {
+	return firstMovePolicyNames[p]
+}
+
+// title returns the description shown on the setup screen.
+func (p firstMovePolicy) title() string {
+	return firstMovePolicyTitles[p]
+}
+
+// parseFirstMovePolicy looks up a policy by name, defaulting to player 1
+// for an empty name.
+func parseFirstMovePolicy(name string) (firstMovePolicy, error) {
+	if name == "" {
+		return firstMovePlayer1, nil
+	}
+	for i, n := range firstMovePolicyNames {
+		if n == name {
+			return firstMovePolicy(i), nil
+		}
+	}
+	return firstMovePlayer1, fmt.Errorf("unknown first move policy %q (available: %s)", name, strings.Join(firstMovePolicyNames, ", "))
+}
+
+// next returns the policy that follows p when cycling through policies.
+func (p firstMovePolicy) next() firstMovePolicy {
+	return firstMovePolicy((int(p) + 1) % len(firstMovePolicyNames))
+}
+
+// cycleFirstMove picks the next policy on the setup screen. The chosen
+// policy takes one press for each name row, so the player who starts can
+// be picked, before moving on.
+func (m *model) cycleFirstMove() {
+	if m.firstMove == firstMoveChosen && m.chosenSeat < len(m.inputs)-1 {
+		m.chosenSeat++
+		return
+	}
+	m.firstMove = m.firstMove.next()
+	m.cfg.FirstMove = m.firstMove.String()
+	m.chosenSeat = 0
+}
+
+// chosenStarter returns the seat picked on the setup screen, or the last
+// seat if rows have been removed since.
+func (m model) chosenStarter() int {
+	return min(m.chosenSeat, len(m.seats)-1)
+}
+
+// firstMoveStatus describes the policy on the setup screen, naming the
+// player picked to start under the chosen policy.
+func (m model) firstMoveStatus() string {
+	if m.firstMove != firstMoveChosen {
+		return "First move: " + m.firstMove.title()
+	}
+	input := m.inputs[min(m.chosenSeat, len(m.inputs)-1)]
+	name := input.Value()
+	if name == "" {
+		name = input.Placeholder
+	}
+	return fmt.Sprintf("First move: %s (%s)", name, m.firstMove.title())
+}
+
+// firstStarter returns the seat that starts the first game of a session.
+func (m model) firstStarter() int {
+	switch m.firstMove {
+	case firstMoveRandom:
+		return rand.IntN(len(m.seats))
+	case firstMoveChosen:
+		return m.chosenStarter()
+	default:
+		return 0
+	}
+}
+
+// nextStarter returns the seat that starts the next game, given how the
+// last one ended. After a draw the loser and winner policies pass the
+// first move on, as there is neither. With more than two sides the player
+// after the winner counts as the loser.
+func (m model) nextStarter() int {
+	n := len(m.seats)
+	switch m.firstMove {
+	case firstMoveAlternate:
+		return (m.starter + 1) % n
+	case firstMoveLoser, firstMoveWinner:
+		if m.winner == "" {
+			return (m.starter + 1) % n
+		}
+		winner := m.seatOf(m.winner)
+		switch {
+		case m.firstMove == firstMoveWinner:
+			return winner
+		case m.sides() == minPlayers:
+			return m.seatOf(opponent(m.winner))
+		default:
+			return (winner + 1) % n
+		}
+	case firstMoveRandom:
+		return rand.IntN(n)
+	case firstMoveChosen:
+		return m.chosenStarter()
+	default:
+		return 0
+	}
+}
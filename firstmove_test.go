@@ -0,0 +1,137 @@
+// firstmove_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// winForX plays a game in which X takes the top row, then presses enter to
+// start the next one.
+func winForX(m model) model {
+	for _, mv := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	return placeAt(m, 0, 0)
+}
+
+// TestFirstMoveAlternate checks that the first move passes to the other
+// player each game, and the score line and turn message follow.
+func TestFirstMoveAlternate(t *testing.T) {
+	m := winForX(setupModel(config{FirstMove: "alternate"}, "Ann", "Bob"))
+	if m.turn != 1 || m.seats[1].marker != "X" || m.seats[0].marker != "O" {
+		t.Fatalf("Expected Bob to start the second game with X, got %v", m.seats)
+	}
+	view := m.View()
+	if !contains(view, "Bob's turn (X)") || !contains(view, "Score: Ann (O) 1 - 0 Bob (X)") {
+		t.Errorf("View does not show Bob starting with Ann's point kept")
+	}
+
+	// Bob's win as X is his point, not player 1's.
+	m = winForX(m)
+	if m.seats[0].score != 1 || m.seats[1].score != 1 {
+		t.Errorf("Expected one point each, got %v", m.seats)
+	}
+	if m.turn != 0 || m.seats[0].marker != "X" {
+		t.Errorf("Expected Ann to start the third game")
+	}
+}
+
+// TestFirstMoveLoserWinner checks the loser and winner policies.
+func TestFirstMoveLoserWinner(t *testing.T) {
+	m := winForX(setupModel(config{FirstMove: "loser"}, "Ann", "Bob"))
+	if m.turn != 1 {
+		t.Errorf("Expected Bob to start after losing, got seat %d", m.turn)
+	}
+
+	m = winForX(setupModel(config{FirstMove: "winner"}, "Ann", "Bob"))
+	if m.turn != 0 {
+		t.Errorf("Expected Ann to start after winning, got seat %d", m.turn)
+	}
+
+	// A draw passes the first move on.
+	m = setupModel(config{FirstMove: "winner"}, "Ann", "Bob")
+	for _, mv := range [][2]int{{0, 0}, {1, 1}, {2, 2}, {0, 1}, {2, 1}, {2, 0}, {0, 2}, {1, 2}, {1, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if !m.isDraw {
+		t.Fatalf("Expected a draw")
+	}
+	m = placeAt(m, 0, 0)
+	if m.turn != 1 {
+		t.Errorf("Expected Bob to start after a draw, got seat %d", m.turn)
+	}
+}
+
+// TestFirstMoveReset checks that abandoning a game keeps the same starter.
+func TestFirstMoveReset(t *testing.T) {
+	m := placeAt(setupModel(config{FirstMove: "alternate"}, "Ann", "Bob"), 0, 0)
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if m = updatedModel.(model); m.turn != 0 {
+		t.Errorf("Expected Ann to start again after a reset, got seat %d", m.turn)
+	}
+}
+
+// TestFirstMoveRotation checks that with three players the markers follow
+// the starter round the table.
+func TestFirstMoveRotation(t *testing.T) {
+	m := playingModel(config{FirstMove: "alternate", Players: []playerConfig{{}, {}, {}}})
+	m.starter = m.nextStarter()
+	m = m.resetGame()
+	for i, want := range []string{"△", "X", "O"} {
+		if m.seats[i].marker != want {
+			t.Errorf("Expected seat %d to play %s, got %s", i, want, m.seats[i].marker)
+		}
+	}
+	m = placeAt(m, 0, 0)
+	if m.turn != 2 || m.player != "O" {
+		t.Errorf("Expected the third player to move second")
+	}
+}
+
+// TestFirstMoveSetup checks that the policy can be picked on the setup
+// screen.
+func TestFirstMoveSetup(t *testing.T) {
+	m := newModel(config{})
+	if !contains(m.View(), "First move: player 1 every game") {
+		t.Fatalf("Setup screen does not show the first move policy")
+	}
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updatedModel.(model)
+	if m.firstMove != firstMoveAlternate || !contains(m.View(), "First move: alternate each game") {
+		t.Errorf("Expected ctrl+f to pick the next policy")
+	}
+
+	// The chosen policy steps through the name rows before moving on.
+	m = setupModel(config{FirstMove: "chosen"}, "Ann", "Bob")
+	if m.starter != 0 {
+		t.Errorf("Expected player 1 to start without a pick, got seat %d", m.starter)
+	}
+	m = newModel(config{FirstMove: "chosen"})
+	m.inputs[1].SetValue("Bob")
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updatedModel.(model)
+	if !contains(m.View(), "First move: Bob (chosen at setup)") {
+		t.Fatalf("Expected ctrl+f to pick Bob to start")
+	}
+	for _, k := range []tea.KeyType{tea.KeyDown, tea.KeyEnter} {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: k})
+		m = updatedModel.(model)
+	}
+	if m.starter != 1 || m.seats[1].marker != "X" {
+		t.Fatalf("Expected Bob to start with X, got seat %d", m.starter)
+	}
+	m = winForX(m)
+	if m.starter != 1 || m.player != "X" || m.turn != 1 {
+		t.Errorf("Expected Bob to start the next game too, got seat %d", m.starter)
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if updatedModel.(model).firstMove != firstMoveChosen {
+		t.Errorf("Expected ctrl+f to do nothing during the game")
+	}
+
+	if _, err := loadConfig(writeConfig(t, `{"first_move": "youngest"}`)); err == nil {
+		t.Errorf("Expected an error for an unknown policy")
+	}
+}
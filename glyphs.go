@@ -0,0 +1,47 @@
+// glyphs.go
+package main
+
+import "fmt"
+
+// Glyph modes accepted by the "glyphs" setting.
+const (
+	glyphsAuto = "auto" // Use big glyphs whenever the terminal has room
+	glyphsOn   = "on"   // Prefer big glyphs, even before the terminal size is known
+	glyphsOff  = "off"  // Always draw single-character markers
+)
+
+// glyphs holds the block art drawn for each marker in big-glyph mode.
+var glyphs = map[string]string{
+	"X": "██   ██\n ██ ██ \n  ███  \n ██ ██ \n██   ██",
+	"O": " █████ \n██   ██\n██   ██\n██   ██\n █████ ",
+	"△": "   █   \n  █ █  \n █   █ \n█     █\n███████",
+	"□": "███████\n█     █\n█     █\n█     █\n███████",
+}
+
+// glyphLayout is the cell layout used to draw big glyphs.
+var glyphLayout = cellLayout{name: "glyph", width: 11, height: 5, bordered: true, glyphs: true}
+
+// validateGlyphMode reports an error for an unknown glyph mode.
+func validateGlyphMode(mode string) error {
+	switch mode {
+	case "", glyphsAuto, glyphsOn, glyphsOff:
+		return nil
+	}
+	return fmt.Errorf("unknown glyph mode %q (available: %s, %s, %s)", mode, glyphsAuto, glyphsOn, glyphsOff)
+}
+
+// layoutsFor returns the candidate cell layouts for a glyph mode, largest first.
+func layoutsFor(mode string) []cellLayout {
+	if mode == glyphsOff {
+		return cellLayouts
+	}
+	return append([]cellLayout{glyphLayout}, cellLayouts...)
+}
+
+// glyph returns the art for a marker, or the marker itself when it has none.
+func glyph(marker string) string {
+	if art, ok := glyphs[marker]; ok {
+		return art
+	}
+	return marker
+}
@@ -0,0 +1,223 @@
+// gomoku.go
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The Gomoku board and the line needed to win.
+const (
+	gomokuSize   = 15
+	gomokuLength = 5
+)
+
+// forbiddenMark is drawn on the points Black may not play under Renju rules.
+const forbiddenMark = "×"
+
+// gomokuRule selects how Gomoku lines are counted.
+type gomokuRule int
+
+const (
+	gomokuFreestyle gomokuRule = iota // Five or more in a row wins
+	gomokuStandard                    // Exactly five in a row wins
+	gomokuRenju                       // Black (X) must make exactly five and may not play forbidden points
+)
+
+// gomokuRuleNames lists the name of each rule, indexed by gomokuRule. These
+// are the names accepted by the "gomoku_rule" setting and the --gomoku-rule
+// flag.
+var gomokuRuleNames = []string{"freestyle", "standard", "renju"}
+
+// gomokuRuleTitles holds the name shown in the header for each rule.
+var gomokuRuleTitles = []string{"Freestyle", "Standard", "Renju"}
+
+// String returns the rule's name.
+func (r gomokuRule) String() string {
+	return gomokuRuleNames[r]
+}
+
+// title returns the name shown in the header.
+func (r gomokuRule) title() string {
+	return gomokuRuleTitles[r]
+}
+
+// parseGomokuRule looks up a rule by name, defaulting to freestyle for an
+// empty name.
+func parseGomokuRule(name string) (gomokuRule, error) {
+	if name == "" {
+		return gomokuFreestyle, nil
+	}
+	for i, n := range gomokuRuleNames {
+		if n == name {
+			return gomokuRule(i), nil
+		}
+	}
+	return gomokuFreestyle, fmt.Errorf("unknown gomoku rule %q (available: %s)", name, strings.Join(gomokuRuleNames, ", "))
+}
+
+// next returns the rule that follows r when cycling through rules.
+func (r gomokuRule) next() gomokuRule {
+	return gomokuRule((int(r) + 1) % len(gomokuRuleNames))
+}
+
+// five returns the winning line through (x, y) for the marker there under
+// rule r, or nil if there is none.
+func (r gomokuRule) five(g grid, x, y int) []struct{ x, y int } {
+	exact := r == gomokuStandard || (r == gomokuRenju && g.cells[y][x] == "X")
+	for _, d := range gridDirections {
+		run := g.run(x, y, d.dx, d.dy)
+		if len(run) == gomokuLength || (!exact && len(run) > gomokuLength) {
+			return run
+		}
+	}
+	return nil
+}
+
+// placeGomoku plays the current player's marker at the cursor. Under Renju
+// rules Black may not play a forbidden point.
+func (m *model) placeGomoku() moveResult {
+	x, y := m.cursorX, m.cursorY
+	if m.grid.cells[y][x] != " " {
+		return moveIllegal
+	}
+	if m.gomokuRule == gomokuRenju && m.player == "X" && renjuForbidden(m.grid.clone(), x, y) {
+		return moveIllegal
+	}
+
+	m.grid = m.grid.clone()
+	m.grid.cells[y][x] = m.player
+	if cells := m.gomokuRule.five(m.grid, x, y); cells != nil {
+		m.winningCells = cells
+		return moveWon
+	}
+	if m.grid.full() {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// forbiddenPoints returns the empty points Black may not play, or nil when
+// no points are forbidden because the rule is not Renju or White is to move.
+// It checks every empty point, so the result is kept in m.forbidden after
+// each move rather than worked out on every redraw.
+func (m model) forbiddenPoints() [][]bool {
+	if m.mode != modeGomoku || m.gomokuRule != gomokuRenju || m.player != "X" || m.winner != "" || m.isDraw {
+		return nil
+	}
+	g := m.grid.clone()
+	forbidden := make([][]bool, g.height)
+	for y := range forbidden {
+		forbidden[y] = make([]bool, g.width)
+		for x := range forbidden[y] {
+			forbidden[y][x] = g.cells[y][x] == " " && renjuForbidden(g, x, y)
+		}
+	}
+	return forbidden
+}
+
+// renjuForbidden reports whether Black playing the empty point (x, y) would
+// make an overline, two fours or two open threes without also making
+// exactly five. It places and removes stones on g while it looks, so g must
+// not be shared. Whether the point that turns a three into an open four is
+// itself forbidden is not checked, which only matters in rare positions.
+func renjuForbidden(g grid, x, y int) bool {
+	g.cells[y][x] = "X"
+	defer func() { g.cells[y][x] = " " }()
+
+	overline := false
+	for _, d := range gridDirections {
+		switch n := len(g.run(x, y, d.dx, d.dy)); {
+		case n == gomokuLength:
+			return false // Five wins, whatever else the move makes
+		case n > gomokuLength:
+			overline = true
+		}
+	}
+	if overline {
+		return true
+	}
+
+	fours, threes := 0, 0
+	for _, d := range gridDirections {
+		if n := renjuFours(g, x, y, d.dx, d.dy); n > 0 {
+			fours += n
+		} else if renjuOpenThree(g, x, y, d.dx, d.dy) {
+			threes++
+		}
+	}
+	return fours >= 2 || threes >= 2
+}
+
+// renjuFours counts the fours through Black's stone at (x, y) in the
+// direction (dx, dy): lines that one more stone would turn into exactly
+// five. A straight four has two such points but counts once.
+func renjuFours(g grid, x, y, dx, dy int) int {
+	var points []int
+	for i := -gomokuLength + 1; i < gomokuLength; i++ {
+		qx, qy := x+i*dx, y+i*dy
+		if i == 0 || !g.inBounds(qx, qy) || g.cells[qy][qx] != " " {
+			continue
+		}
+		g.cells[qy][qx] = "X"
+		if run := g.run(x, y, dx, dy); len(run) == gomokuLength && containsCell(run, qx, qy) {
+			points = append(points, i)
+		}
+		g.cells[qy][qx] = " "
+	}
+	if len(points) == 2 && points[1]-points[0] == gomokuLength {
+		return 1 // Both ends of the same straight four
+	}
+	return len(points)
+}
+
+// renjuOpenThree reports whether one more Black stone in the direction
+// (dx, dy) would make a straight four through (x, y): four in a row with
+// both ends free, so it cannot be stopped.
+func renjuOpenThree(g grid, x, y, dx, dy int) bool {
+	for i := -gomokuLength + 2; i < gomokuLength-1; i++ {
+		qx, qy := x+i*dx, y+i*dy
+		if i == 0 || !g.inBounds(qx, qy) || g.cells[qy][qx] != " " {
+			continue
+		}
+		g.cells[qy][qx] = "X"
+		straight := renjuStraightFour(g, x, y, dx, dy)
+		g.cells[qy][qx] = " "
+		if straight {
+			return true
+		}
+	}
+	return false
+}
+
+// renjuStraightFour reports whether the run through (x, y) in the direction
+// (dx, dy) is four long with both ends free, each making exactly five.
+func renjuStraightFour(g grid, x, y, dx, dy int) bool {
+	run := g.run(x, y, dx, dy)
+	if len(run) != gomokuLength-1 {
+		return false
+	}
+	first, last := run[0], run[len(run)-1]
+	for _, end := range []struct{ x, y int }{{first.x - dx, first.y - dy}, {last.x + dx, last.y + dy}} {
+		if !g.inBounds(end.x, end.y) || g.cells[end.y][end.x] != " " {
+			return false
+		}
+		g.cells[end.y][end.x] = "X"
+		five := len(g.run(end.x, end.y, dx, dy)) == gomokuLength
+		g.cells[end.y][end.x] = " "
+		if !five {
+			return false
+		}
+	}
+	return true
+}
+
+// containsCell reports whether cells includes (x, y).
+func containsCell(cells []struct{ x, y int }, x, y int) bool {
+	for _, c := range cells {
+		if c.x == x && c.y == y {
+			return true
+		}
+	}
+	return false
+}
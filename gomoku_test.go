@@ -0,0 +1,126 @@
+// gomoku_test.go
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stones places X at each of the given points without taking turns.
+func stones(m model, player string, points ...struct{ x, y int }) model {
+	for _, p := range points {
+		m.grid.cells[p.y][p.x] = player
+	}
+	m.forbidden = m.forbiddenPoints()
+	return m
+}
+
+// row returns the points (x, y) for each x in xs.
+func row(y int, xs ...int) []struct{ x, y int } {
+	var points []struct{ x, y int }
+	for _, x := range xs {
+		points = append(points, struct{ x, y int }{x, y})
+	}
+	return points
+}
+
+// TestGomokuRules checks which lines win under each rule.
+func TestGomokuRules(t *testing.T) {
+	tests := []struct {
+		rule   string
+		player string
+		xs     []int // Stones already on row 7; the move is played at x = 5
+		want   int   // Length of the winning line, 0 for none
+	}{
+		{"freestyle", "X", []int{1, 2, 3, 4}, 5},
+		{"freestyle", "X", []int{1, 2, 3, 4, 6}, 6},
+		{"standard", "X", []int{1, 2, 3, 4}, 5},
+		{"standard", "X", []int{1, 2, 3, 4, 6}, 0},
+		{"renju", "O", []int{1, 2, 3, 4, 6}, 6},
+	}
+
+	for _, tt := range tests {
+		m := playingModel(config{Mode: "gomoku", GomokuRule: tt.rule})
+		if m.grid.width != 15 || m.grid.height != 15 {
+			t.Fatalf("Expected a 15x15 board, got %dx%d", m.grid.width, m.grid.height)
+		}
+		m = stones(m, tt.player, row(7, tt.xs...)...)
+		m.player = tt.player
+		m = placeAt(m, 5, 7)
+		if got := len(m.winningCells); got != tt.want {
+			t.Errorf("%s %s: expected a winning line of %d, got %d", tt.rule, tt.player, tt.want, got)
+		}
+		if tt.want > 0 && (m.winner != tt.player || !m.isWinningCell(5, 7)) {
+			t.Errorf("%s %s: expected %s to win through the move", tt.rule, tt.player, tt.player)
+		}
+	}
+}
+
+// TestRenjuForbidden checks the forbidden points for Black under Renju rules.
+func TestRenjuForbidden(t *testing.T) {
+	tests := []struct {
+		name      string
+		black     []struct{ x, y int }
+		x, y      int
+		forbidden bool
+	}{
+		{"overline", row(7, 1, 2, 3, 4, 6), 5, 7, true},
+		{"five", row(7, 1, 2, 3, 4), 5, 7, false},
+		// Two open threes cross at (7, 7).
+		{"three-three", append(row(7, 5, 6), struct{ x, y int }{7, 5}, struct{ x, y int }{7, 6}), 7, 7, true},
+		// Two fours cross at (7, 7).
+		{"four-four", append(row(7, 4, 5, 6), struct{ x, y int }{7, 4}, struct{ x, y int }{7, 5}, struct{ x, y int }{7, 6}), 7, 7, true},
+		// Two fours on one line: X.XXX.X around the move.
+		{"four-four in a line", row(7, 3, 5, 6, 9), 7, 7, true},
+		{"single three", row(7, 5, 6), 7, 7, false},
+		{"four and three", append(row(7, 4, 5, 6), struct{ x, y int }{7, 5}, struct{ x, y int }{7, 6}), 7, 7, false},
+	}
+
+	for _, tt := range tests {
+		m := playingModel(config{Mode: "gomoku", GomokuRule: "renju"})
+		m = stones(m, "X", tt.black...)
+		if got := m.forbiddenPoints()[tt.y][tt.x]; got != tt.forbidden {
+			t.Errorf("%s: expected forbidden %v, got %v", tt.name, tt.forbidden, got)
+		}
+	}
+}
+
+// TestRenjuForbiddenMove checks that Black cannot play a forbidden point and
+// that it is marked on the board.
+func TestRenjuForbiddenMove(t *testing.T) {
+	m := playingModel(config{Mode: "gomoku", GomokuRule: "renju"})
+	m = stones(m, "X", row(7, 1, 2, 3, 4, 6)...)
+	if !contains(m.View(), forbiddenMark) {
+		t.Errorf("Expected the forbidden point to be marked")
+	}
+	m = placeAt(m, 5, 7)
+	if m.grid.cells[7][5] != " " || m.player != "X" {
+		t.Errorf("Expected Black's overline to be rejected")
+	}
+
+	// White may play there, and is not shown forbidden points.
+	m.player = "O"
+	if m.forbiddenPoints() != nil {
+		t.Errorf("Expected no forbidden points for White")
+	}
+	m = placeAt(m, 5, 7)
+	if m.grid.cells[7][5] != "O" {
+		t.Errorf("Expected White to play the point")
+	}
+}
+
+// TestGomokuFits checks that the 15x15 board fits an 80x24 terminal, with
+// and without a clock.
+func TestGomokuFits(t *testing.T) {
+	for _, cfg := range []config{{Mode: "gomoku"}, {Mode: "gomoku", GomokuRule: "renju", timeControl: timeControl{PerGame: time.Minute}}} {
+		m := playingModel(cfg)
+		updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+		view := updatedModel.(model).View()
+		if contains(view, "Terminal too small") || lipgloss.Height(view) > 24 {
+			t.Errorf("Expected the board to fit in 80x24, got:\n%s", view)
+		}
+	}
+}
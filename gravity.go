@@ -0,0 +1,81 @@
+// gravity.go
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gravityDefaults is the Connect Four board used when the config sets no size.
+var gravityDefaults = gridConfig{Cols: 7, Rows: 6, Connect: 4}
+
+// newGravityGrid returns an empty grid for gravity mode sized from the config.
+func newGravityGrid(gc gridConfig) grid {
+	gc = gc.withDefaults(gravityDefaults.sizedFor(gc))
+	return newGrid(gc.Cols, gc.Rows, gc.Connect)
+}
+
+// dropRow returns the lowest empty row in column x, or -1 if it is full.
+func (g grid) dropRow(x int) int {
+	for y := g.height - 1; y >= 0; y-- {
+		if g.cells[y][x] == " " {
+			return y
+		}
+	}
+	return -1
+}
+
+// placeGravity drops the current player's marker down the cursor's column.
+func (m *model) placeGravity() moveResult {
+	y := m.grid.dropRow(m.cursorX)
+	if y < 0 {
+		return moveIllegal
+	}
+
+	m.grid = m.grid.clone()
+	m.grid.cells[y][m.cursorX] = m.player
+	if cells := m.grid.lineThrough(m.cursorX, y); cells != nil {
+		m.winningCells = cells
+		return moveWon
+	}
+	if m.grid.full() {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// viewGravityBoard draws the grid with a drop indicator above the cursor's
+// column. It returns the size needed and false when it does not fit.
+func viewGravityBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	g := m.grid
+	// One extra line holds the drop indicator.
+	lay, ok := chooseLayout(m.cfg.Glyphs, m.width, m.height, g.width, g.height, reservedLines+1)
+	w, h := lay.boardSize(g.width, g.height)
+	needWidth, needHeight = w, h+reservedLines+1
+	if !ok {
+		return "", needWidth, needHeight, false
+	}
+
+	cellWidth, _ := lay.outerSize()
+	var indicator strings.Builder
+	for x := 0; x < g.width; x++ {
+		mark := " "
+		if x == m.cursorX && m.winner == "" && !m.isDraw {
+			mark = lipgloss.NewStyle().Foreground(m.theme.cursor).Render("▼")
+		}
+		indicator.WriteString(lipgloss.PlaceHorizontal(cellWidth, lipgloss.Center, mark))
+	}
+
+	var rows []string
+	for y := 0; y < g.height; y++ {
+		var rowItems []string
+		for x := 0; x < g.width; x++ {
+			rowItems = append(rowItems, renderCell(m, lay, g.cells[y][x], false, m.isWinningCell(x, y)))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
+	}
+
+	board := lipgloss.JoinVertical(lipgloss.Left, rows...)
+	return indicator.String() + "\n" + board, needWidth, needHeight, true
+}
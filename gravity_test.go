@@ -0,0 +1,107 @@
+// gravity_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestGravityDrop checks that markers fall to the lowest empty cell.
+func TestGravityDrop(t *testing.T) {
+	m := playingModel(config{Mode: "gravity", Grid: gridConfig{}})
+	if m.grid.width != 7 || m.grid.height != 6 || m.grid.winLength != 4 {
+		t.Fatalf("Expected the default 7x6 connect-4 grid, got %dx%d connect-%d", m.grid.width, m.grid.height, m.grid.winLength)
+	}
+
+	m = dropIn(m, 3)
+	m = dropIn(m, 3)
+	if m.grid.cells[5][3] != "X" || m.grid.cells[4][3] != "O" {
+		t.Errorf("Expected X then O stacked in column 3, got %q and %q", m.grid.cells[5][3], m.grid.cells[4][3])
+	}
+}
+
+// TestGravityFullColumn checks that a full column rejects further markers.
+func TestGravityFullColumn(t *testing.T) {
+	m := playingModel(config{Mode: "gravity", Grid: gridConfig{Cols: 4, Rows: 3, Connect: 4}})
+	for i := 0; i < 3; i++ {
+		m = dropIn(m, 0)
+	}
+	player := m.player
+	m = dropIn(m, 0)
+	if m.player != player || m.moves != 3 {
+		t.Errorf("Expected a drop into a full column to be rejected")
+	}
+}
+
+// TestGravityConnect checks that a line longer than the board is rejected,
+// and that asking only for a longer line widens the default board.
+func TestGravityConnect(t *testing.T) {
+	for _, gc := range []gridConfig{{Cols: 4, Rows: 3, Connect: 5}, {Connect: 20}} {
+		if err := gc.validate(); err == nil {
+			t.Errorf("Expected an error for %+v", gc)
+		}
+	}
+
+	m := playingModel(config{Mode: "gravity", Grid: gridConfig{Connect: 8}})
+	if m.grid.width != 8 || m.grid.height != 8 || m.grid.winLength != 8 {
+		t.Errorf("Expected an 8x8 connect-8 grid, got %dx%d connect-%d", m.grid.width, m.grid.height, m.grid.winLength)
+	}
+}
+
+// TestGravityWin covers horizontal, vertical and diagonal wins on non-square grids.
+func TestGravityWin(t *testing.T) {
+	testCases := map[string]struct {
+		grid  gridConfig
+		drops []int
+	}{
+		"Horizontal on 7x6": {
+			grid:  gridConfig{},
+			drops: []int{0, 0, 1, 1, 2, 2, 3},
+		},
+		"Vertical on 5x4 connect 3": {
+			grid:  gridConfig{Cols: 5, Rows: 4, Connect: 3},
+			drops: []int{4, 0, 4, 0, 4},
+		},
+		"Diagonal on 7x6": {
+			grid:  gridConfig{},
+			drops: []int{0, 1, 1, 2, 2, 3, 2, 3, 3, 6, 3},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m := playingModel(config{Mode: "gravity", Grid: tc.grid})
+			for _, x := range tc.drops {
+				m = dropIn(m, x)
+			}
+			if m.winner != "X" {
+				t.Fatalf("Expected X to win, got %q", m.winner)
+			}
+			if len(m.winningCells) < m.grid.winLength {
+				t.Errorf("Expected at least %d winning cells, got %d", m.grid.winLength, len(m.winningCells))
+			}
+		})
+	}
+}
+
+// TestGravityCursor checks that the cursor only moves sideways.
+func TestGravityCursor(t *testing.T) {
+	m := playingModel(config{Mode: "gravity", Grid: gridConfig{}})
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(model)
+	if m.cursorY != 0 {
+		t.Errorf("Expected cursorY to stay 0 in gravity mode, got %d", m.cursorY)
+	}
+
+	for i := 0; i < 10; i++ {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		m = updatedModel.(model)
+	}
+	if m.cursorX != 6 {
+		t.Errorf("Expected cursorX to stop at 6, got %d", m.cursorX)
+	}
+	if !contains(m.View(), "▼") {
+		t.Errorf("View does not show the drop indicator")
+	}
+}
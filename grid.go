@@ -0,0 +1,215 @@
+// grid.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// grid is a rectangular board of any size on which a player wins by making
+// a line of winLength of their markers. It backs the modes whose board is not
+// the fixed 3x3 one.
+type grid struct {
+	cells     [][]string // cells[y][x], " " when empty
+	width     int
+	height    int
+	winLength int
+	torus     bool                   // Lines wrap round the edges
+	lines     [][]struct{ x, y int } // Every line of a torus, from gridLines
+}
+
+// gridDirections are the four directions a line can run in: across, down and
+// the two diagonals.
+var gridDirections = []struct{ dx, dy int }{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// newGrid returns an empty width×height grid.
+func newGrid(width, height, winLength int) grid {
+	cells := make([][]string, height)
+	for y := range cells {
+		cells[y] = make([]string, width)
+		for x := range cells[y] {
+			cells[y][x] = " "
+		}
+	}
+	return grid{cells: cells, width: width, height: height, winLength: winLength}
+}
+
+// clone returns a deep copy, so a model holding the grid can be copied by
+// value without sharing cells.
+func (g grid) clone() grid {
+	cells := make([][]string, len(g.cells))
+	for y := range g.cells {
+		cells[y] = append([]string(nil), g.cells[y]...)
+	}
+	g.cells = cells
+	return g
+}
+
+// inBounds reports whether (x, y) lies on the grid.
+func (g grid) inBounds(x, y int) bool {
+	return x >= 0 && x < g.width && y >= 0 && y < g.height
+}
+
+// full reports whether every cell is taken.
+func (g grid) full() bool {
+	for _, row := range g.cells {
+		for _, cell := range row {
+			if cell == " " {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lineThrough returns the longest run of the marker at (x, y) through that
+// cell if it is at least winLength long, or nil otherwise. On a torus it
+// returns a line of the generated set through the cell instead, as a run
+// there has no start or end.
+func (g grid) lineThrough(x, y int) []struct{ x, y int } {
+	player := g.cells[y][x]
+	if player == " " {
+		return nil
+	}
+	if g.torus {
+		var through [][]struct{ x, y int }
+		for _, line := range g.lines {
+			if containsCell(line, x, y) {
+				through = append(through, line)
+			}
+		}
+		return lineOf(through, player, func(x, y int) string { return g.cells[y][x] })
+	}
+	for _, d := range gridDirections {
+		if run := g.run(x, y, d.dx, d.dy); len(run) >= g.winLength {
+			return run
+		}
+	}
+	return nil
+}
+
+// run returns the unbroken run of the marker at (x, y) through that cell in
+// the direction (dx, dy), in order along the direction.
+func (g grid) run(x, y, dx, dy int) []struct{ x, y int } {
+	player := g.cells[y][x]
+	// Walk back to the start of the run, then collect it forwards.
+	sx, sy := x, y
+	for g.inBounds(sx-dx, sy-dy) && g.cells[sy-dy][sx-dx] == player {
+		sx, sy = sx-dx, sy-dy
+	}
+	var run []struct{ x, y int }
+	for cx, cy := sx, sy; g.inBounds(cx, cy) && g.cells[cy][cx] == player; cx, cy = cx+dx, cy+dy {
+		run = append(run, struct{ x, y int }{cx, cy})
+	}
+	return run
+}
+
+// gridConfig is the config file form of a grid's dimensions. Zero values
+// fall back to the defaults of the mode being played.
+type gridConfig struct {
+	Cols    int `json:"cols"`
+	Rows    int `json:"rows"`
+	Connect int `json:"connect"`
+}
+
+// Limits on the grid size, so the board stays playable in a terminal.
+const (
+	minGridSide = 3
+	maxGridSide = 19
+)
+
+// validate reports an error for dimensions outside the supported limits.
+func (gc gridConfig) validate() error {
+	for _, side := range []int{gc.Cols, gc.Rows} {
+		if side != 0 && (side < minGridSide || side > maxGridSide) {
+			return fmt.Errorf("grid sides must be between %d and %d, got %d", minGridSide, maxGridSide, side)
+		}
+	}
+	if gc.Connect != 0 && (gc.Connect < minGridSide || gc.Connect > maxGridSide) {
+		return fmt.Errorf("connect must be between %d and %d, got %d", minGridSide, maxGridSide, gc.Connect)
+	}
+	if gc.Cols != 0 && gc.Rows != 0 && gc.Connect > max(gc.Cols, gc.Rows) {
+		return fmt.Errorf("connect %d does not fit on a %dx%d grid", gc.Connect, gc.Cols, gc.Rows)
+	}
+	return nil
+}
+
+// withDefaults fills in unset dimensions from def.
+func (gc gridConfig) withDefaults(def gridConfig) gridConfig {
+	if gc.Cols == 0 {
+		gc.Cols = def.Cols
+	}
+	if gc.Rows == 0 {
+		gc.Rows = def.Rows
+	}
+	if gc.Connect == 0 {
+		gc.Connect = def.Connect
+	}
+	return gc
+}
+
+// sizedFor returns the default board def with its sides made at least as
+// long as the line to win in gc, so that asking only for a longer line than
+// the mode's default board gives a board it can be won on.
+func (def gridConfig) sizedFor(gc gridConfig) gridConfig {
+	def.Cols, def.Rows = max(def.Cols, gc.Connect), max(def.Rows, gc.Connect)
+	return def
+}
+
+// multiplayerDefaults sizes the classic board for three or more players:
+// two cells wider than the number of players, with three in a row to win.
+func multiplayerDefaults(players int) gridConfig {
+	return gridConfig{Cols: players + 2, Rows: players + 2, Connect: 3}
+}
+
+// placeGrid plays the current player's marker at the cursor.
+func (m *model) placeGrid() moveResult {
+	x, y := m.cursorX, m.cursorY
+	if m.grid.cells[y][x] != " " {
+		return moveIllegal
+	}
+
+	m.grid = m.grid.clone()
+	m.grid.cells[y][x] = m.player
+	if cells := m.grid.lineThrough(x, y); cells != nil {
+		m.winningCells = cells
+		return moveWon
+	}
+	if m.grid.full() {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// viewGrid draws the grid with the cursor on it, marking the points Black
+// may not play under Renju rules. It returns the size needed and false when
+// it does not fit.
+func viewGrid(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	g := m.grid
+	lay, ok := chooseLayout(m.cfg.Glyphs, m.width, m.height, g.width, g.height, reservedLines)
+	w, h := lay.boardSize(g.width, g.height)
+	needWidth, needHeight = w, h+reservedLines
+	if !ok {
+		return "", needWidth, needHeight, false
+	}
+
+	forbidden := m.forbidden
+	var rows []string
+	for y := 0; y < g.height; y++ {
+		var rowItems []string
+		for x := 0; x < g.width; x++ {
+			cell := g.cells[y][x]
+			if forbidden != nil && forbidden[y][x] {
+				cell = lipgloss.NewStyle().Faint(true).Render(forbiddenMark)
+			}
+			if cell == blockedCell {
+				cell = lipgloss.NewStyle().Foreground(m.theme.border).Render(blockedMark)
+			}
+			isCursor := m.cursorX == x && m.cursorY == y
+			rowItems = append(rowItems, renderCell(m, lay, cell, isCursor, m.isWinningCell(x, y)))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...), needWidth, needHeight, true
+}
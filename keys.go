@@ -0,0 +1,374 @@
+// keys.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds every key binding the game responds to. The help footer is
+// rendered from these bindings, so it always matches what the game accepts.
+type keyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	PrevLayer   key.Binding
+	NextLayer   key.Binding
+	Place       key.Binding
+	Marker      key.Binding
+	Number      key.Binding
+	Reset       key.Binding
+	NewSession  key.Binding
+	Theme       key.Binding
+	TimeControl key.Binding
+	Mode        key.Binding
+	Misere      key.Binding
+	Disappear   key.Binding
+	Wrap        key.Binding
+	Opening     key.Binding
+	Swap        key.Binding
+	Extend      key.Binding
+	GomokuRule  key.Binding
+	Hint        key.Binding
+	NextPuzzle  key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+
+	// Bindings used on the name input screen.
+	Submit       key.Binding
+	PrevField    key.Binding
+	NextField    key.Binding
+	AddPlayer    key.Binding
+	RemovePlayer key.Binding
+	Teams        key.Binding
+	FirstMove    key.Binding
+	Symbol       key.Binding
+}
+
+// defaultKeyMap returns the built-in key bindings.
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "move down"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "move left"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "move right"),
+		),
+		PrevLayer: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous layer"),
+		),
+		NextLayer: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next layer"),
+		),
+		Place: key.NewBinding(
+			key.WithKeys("enter", " "),
+			key.WithHelp("enter/space", "place marker"),
+		),
+		Marker: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "switch marker"),
+		),
+		Number: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			key.WithHelp("1-9", "pick number"),
+		),
+		Reset: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reset game"),
+		),
+		NewSession: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reset scores and names"),
+		),
+		Theme: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "next theme"),
+		),
+		TimeControl: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "time control (before first move)"),
+		),
+		Mode: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "game mode (before first move)"),
+		),
+		Misere: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "misère on/off (before first move)"),
+		),
+		Disappear: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "disappearing marks on/off (before first move)"),
+		),
+		Wrap: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "torus board on/off (before first move)"),
+		),
+		Opening: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "opening rule (before first move)"),
+		),
+		Swap: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "swap sides"),
+		),
+		Extend: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "place two more stones"),
+		),
+		Hint: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "hint"),
+		),
+		NextPuzzle: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next puzzle"),
+		),
+		GomokuRule: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "gomoku rule (before first move)"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("ctrl+c", "q"),
+			key.WithHelp("q/ctrl+c", "quit"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "continue"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous field"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next field"),
+		),
+		AddPlayer: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "add player"),
+		),
+		RemovePlayer: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "remove player"),
+		),
+		Teams: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "team play"),
+		),
+		FirstMove: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "who starts"),
+		),
+		Symbol: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "change symbol"),
+		),
+	}
+}
+
+// applyMode enables the bindings that make sense in the given mode and for
+// the number of players. In gravity mode markers drop down a column, so the
+// cursor only moves sideways; only the 3D mode has layers to move between,
+// only wild and Order and Chaos players choose their marker, and only
+// numerical players pick numbers. The clock and misère play are for two
+// players only.
+func (k *keyMap) applyMode(mode gameMode, players int) {
+	k.Up.SetEnabled(mode != modeGravity)
+	k.Down.SetEnabled(mode != modeGravity)
+	k.PrevLayer.SetEnabled(mode == modeQubic)
+	k.NextLayer.SetEnabled(mode == modeQubic)
+	k.Marker.SetEnabled(mode == modeWild || mode == modeOrderChaos)
+	k.Number.SetEnabled(mode == modeNumerical)
+	k.Misere.SetEnabled(mode != modeNotakto && mode != modeOrderChaos && mode != modeQuantum && mode != modePuzzle && players <= minPlayers)
+	k.TimeControl.SetEnabled(players <= minPlayers)
+	k.Disappear.SetEnabled(mode == modeClassic && players <= minPlayers)
+	k.Wrap.SetEnabled(mode == modeClassic)
+	openings := len(mode.openings()) > 0 && players <= minPlayers
+	k.Opening.SetEnabled(openings)
+	k.Swap.SetEnabled(openings)
+	k.Extend.SetEnabled(openings && mode == modeGomoku)
+	k.GomokuRule.SetEnabled(mode == modeGomoku)
+	k.Hint.SetEnabled(mode == modePuzzle)
+	k.NextPuzzle.SetEnabled(mode == modePuzzle)
+}
+
+// ShortHelp returns the bindings shown in the compact help footer.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Place, k.Marker, k.Number, k.Hint, k.Reset, k.Help, k.Quit}
+}
+
+// FullHelp returns the bindings shown when the full help is toggled on.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right, k.PrevLayer, k.NextLayer},
+		{k.Place, k.Marker, k.Number, k.Swap, k.Extend, k.Hint, k.NextPuzzle, k.Reset, k.NewSession},
+		{k.Mode, k.Misere, k.Disappear, k.Wrap, k.Opening, k.GomokuRule, k.TimeControl},
+		{k.Theme, k.Help, k.Quit},
+	}
+}
+
+// applySetup enables adding and removing players on the name input screen
+// while there is room for more or more than the minimum. A team game always
+// has two teams of two.
+func (k *keyMap) applySetup(players int, mode gameMode, teams bool) {
+	k.AddPlayer.SetEnabled(!teams && players < mode.playerLimit())
+	k.RemovePlayer.SetEnabled(!teams && players > minPlayers)
+}
+
+// setupHelp returns the bindings shown on the name input screen.
+func (k keyMap) setupHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.PrevField, k.NextField, k.AddPlayer, k.RemovePlayer, k.Teams, k.FirstMove, k.Symbol, k.Quit}
+}
+
+// matchHelp returns the bindings shown on the match summary screen.
+func (k keyMap) matchHelp() []key.Binding {
+	return []key.Binding{k.Place, k.NewSession, k.Quit}
+}
+
+// bindings maps the names used in the config file to their bindings.
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":            &k.Up,
+		"down":          &k.Down,
+		"left":          &k.Left,
+		"right":         &k.Right,
+		"prev_layer":    &k.PrevLayer,
+		"next_layer":    &k.NextLayer,
+		"place":         &k.Place,
+		"marker":        &k.Marker,
+		"number":        &k.Number,
+		"reset":         &k.Reset,
+		"new_session":   &k.NewSession,
+		"theme":         &k.Theme,
+		"time_control":  &k.TimeControl,
+		"mode":          &k.Mode,
+		"misere":        &k.Misere,
+		"disappear":     &k.Disappear,
+		"wrap":          &k.Wrap,
+		"opening":       &k.Opening,
+		"swap":          &k.Swap,
+		"extend":        &k.Extend,
+		"gomoku_rule":   &k.GomokuRule,
+		"hint":          &k.Hint,
+		"next_puzzle":   &k.NextPuzzle,
+		"help":          &k.Help,
+		"quit":          &k.Quit,
+		"submit":        &k.Submit,
+		"prev_field":    &k.PrevField,
+		"next_field":    &k.NextField,
+		"add_player":    &k.AddPlayer,
+		"remove_player": &k.RemovePlayer,
+		"teams":         &k.Teams,
+		"first_move":    &k.FirstMove,
+		"symbol":        &k.Symbol,
+	}
+}
+
+// applyOverrides rebinds the named actions to the given keys, updating the
+// help text so the footer reflects the new bindings.
+func (k *keyMap) applyOverrides(overrides map[string][]string) error {
+	bindings := k.bindings()
+	for name, keys := range overrides {
+		b, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("key binding %q has no keys", name)
+		}
+		// Each number is picked with its own digit, so only digits can
+		// be bound.
+		for _, k := range keys {
+			if _, ok := numberKey(k); name == "number" && !ok {
+				return fmt.Errorf("key binding %q takes the digits 1-9, got %q", name, k)
+			}
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(helpKeyLabel(keys), b.Help().Desc)
+	}
+
+	// A rebound key is taken from the defaults of the other actions on the
+	// same screen, so binding "s" to move down doesn't also swap sides.
+	for name, b := range bindings {
+		if _, ok := overrides[name]; ok {
+			continue
+		}
+		var kept []string
+		for _, key := range b.Keys() {
+			if !claimedKey(overrides, key, setupAction(name)) {
+				kept = append(kept, key)
+			}
+		}
+		switch {
+		case len(kept) == 0:
+			b.Unbind()
+		case len(kept) < len(b.Keys()):
+			b.SetKeys(kept...)
+			b.SetHelp(helpKeyLabel(kept), b.Help().Desc)
+		}
+	}
+	return nil
+}
+
+// setupAction reports whether the named action belongs to the name input
+// screen rather than the game.
+func setupAction(name string) bool {
+	switch name {
+	case "submit", "prev_field", "next_field", "add_player", "remove_player", "teams", "first_move", "symbol":
+		return true
+	}
+	return false
+}
+
+// claimedKey reports whether an override on the same screen binds key.
+func claimedKey(overrides map[string][]string, key string, setup bool) bool {
+	for name, keys := range overrides {
+		if setupAction(name) == setup && slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// helpKeyLabel builds the short key label shown in the help footer.
+func helpKeyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case " ":
+			labels[i] = "space"
+		case "up":
+			labels[i] = "↑"
+		case "down":
+			labels[i] = "↓"
+		case "left":
+			labels[i] = "←"
+		case "right":
+			labels[i] = "→"
+		default:
+			labels[i] = k
+		}
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,86 @@
+// layout.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// cellLayout describes how large each board cell is drawn.
+type cellLayout struct {
+	name     string
+	width    int  // Inner width of a cell
+	height   int  // Inner height of a cell
+	bordered bool // Whether each cell gets its own border
+	glyphs   bool // Whether markers are drawn as big block art
+}
+
+// cellLayouts lists the available cell sizes, largest first.
+var cellLayouts = []cellLayout{
+	{name: "large", width: 9, height: 3, bordered: true},
+	{name: "normal", width: 5, height: 1, bordered: true},
+	{name: "compact", width: 3, height: 1, bordered: false},
+}
+
+// defaultLayout is used until the terminal size is known.
+var defaultLayout = cellLayouts[1]
+
+// outerSize returns the space one cell takes up, including its border.
+func (c cellLayout) outerSize() (w, h int) {
+	if c.bordered {
+		return c.width + 2, c.height + 2
+	}
+	return c.width, c.height
+}
+
+// boardSize returns the space a cols×rows board takes up with this layout.
+func (c cellLayout) boardSize(cols, rows int) (w, h int) {
+	cw, ch := c.outerSize()
+	return cw * cols, ch * rows
+}
+
+// chooseLayout picks the largest cell layout allowed by the glyph mode for
+// which a cols×rows board fits in a width×height terminal alongside
+// reservedLines of surrounding text. It reports false when not even the
+// compact layout fits. An unknown terminal size (zero) gets the default
+// layout, or big glyphs when they are switched on.
+func chooseLayout(glyphMode string, width, height, cols, rows, reservedLines int) (cellLayout, bool) {
+	candidates := layoutsFor(glyphMode)
+	if width <= 0 || height <= 0 {
+		if glyphMode == glyphsOn {
+			return glyphLayout, true
+		}
+		return defaultLayout, true
+	}
+	for _, c := range candidates {
+		w, h := c.boardSize(cols, rows)
+		if w <= width && h+reservedLines <= height {
+			return c, true
+		}
+	}
+	return candidates[len(candidates)-1], false
+}
+
+// place centres each line of the view in the terminal once its size is known.
+func (m model) place(view string) string {
+	if m.width <= 0 || m.height <= 0 {
+		return view
+	}
+	// Clip over-long lines before centring so they don't skew the alignment.
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, m.width, "")
+	}
+	view = lipgloss.NewStyle().Align(lipgloss.Center).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+}
+
+// viewTooSmall explains that the board cannot fit in the terminal.
+func viewTooSmall(m model, needWidth, needHeight int) string {
+	msg := fmt.Sprintf("Terminal too small.\nNeed %dx%d, have %dx%d.\n\nResize the window or press %s to quit.",
+		needWidth, needHeight, m.width, m.height, m.keys.Quit.Help().Key)
+	return m.place(lipgloss.NewStyle().Width(m.width).Render(msg))
+}
@@ -0,0 +1,86 @@
+// layout_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestChooseLayout checks that cell size follows the terminal size.
+func TestChooseLayout(t *testing.T) {
+	testCases := map[string]struct {
+		glyphs        string
+		width, height int
+		want          string
+		wantOK        bool
+	}{
+		"Unknown size uses the default": {width: 0, height: 0, want: defaultLayout.name, wantOK: true},
+		"Unknown size with glyphs on":   {glyphs: glyphsOn, width: 0, height: 0, want: "glyph", wantOK: true},
+		"Huge terminal uses big glyphs": {width: 120, height: 40, want: "glyph", wantOK: true},
+		"Glyphs off uses large cells":   {glyphs: glyphsOff, width: 120, height: 40, want: "large", wantOK: true},
+		"Big terminal uses large cells": {width: 80, height: 30, want: "large", wantOK: true},
+		"Standard terminal":             {width: 80, height: 24, want: "normal", wantOK: true},
+		"Small terminal is compact":     {width: 30, height: 14, want: "compact", wantOK: true},
+		"Tiny terminal does not fit":    {width: 8, height: 4, want: "compact", wantOK: false},
+		"Glyphs on still falls back":    {glyphs: glyphsOn, width: 30, height: 14, want: "compact", wantOK: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := chooseLayout(tc.glyphs, tc.width, tc.height, 3, 3, 10)
+			if got.name != tc.want || ok != tc.wantOK {
+				t.Errorf("chooseLayout() = %s, %v; want %s, %v", got.name, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+// TestUpdateWindowSize checks that the terminal size is tracked and drives the view.
+func TestUpdateWindowSize(t *testing.T) {
+	m := playingModel(config{})
+
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 20, Height: 6})
+	m = updatedModel.(model)
+	if m.width != 20 || m.height != 6 {
+		t.Fatalf("Expected size 20x6, got %dx%d", m.width, m.height)
+	}
+	if !contains(m.View(), "Terminal too small.") {
+		t.Errorf("View does not report that the terminal is too small")
+	}
+
+	updatedModel, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = updatedModel.(model)
+	if contains(m.View(), "Terminal too small.") {
+		t.Errorf("View reports a too small terminal at 80x24")
+	}
+
+	// A new session keeps the known terminal size.
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(model)
+	if m.width != 80 || m.height != 24 {
+		t.Errorf("Expected size 80x24 after ctrl+r, got %dx%d", m.width, m.height)
+	}
+}
+
+// TestViewBigGlyphs checks that markers are drawn as block art when glyphs are on.
+func TestViewBigGlyphs(t *testing.T) {
+	m := playingModel(config{Glyphs: glyphsOn})
+	m.board[0][0] = "X"
+	if !contains(m.View(), "  ███  ") {
+		t.Errorf("View does not draw X as a big glyph")
+	}
+
+	m = playingModel(config{Glyphs: glyphsOff})
+	m.board[0][0] = "X"
+	m, _ = sized(m, 120, 40)
+	if contains(m.View(), "███") {
+		t.Errorf("View draws big glyphs although they are switched off")
+	}
+}
+
+// sized sends a window size message to the model.
+func sized(m model, width, height int) (model, tea.Cmd) {
+	updatedModel, cmd := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updatedModel.(model), cmd
+}
@@ -0,0 +1,76 @@
+// lines.go
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// classicLines lists the eight lines of the 3x3 board.
+var classicLines = gridLines(3, 3, 3, false)
+
+// gridLines generates every line of length cells on a width×height board,
+// running in each of gridDirections. On a torus lines continue across the
+// edges, coming back in on the opposite side; a line that wraps all the way
+// round is listed once, whichever cell it is started from.
+func gridLines(width, height, length int, torus bool) [][]struct{ x, y int } {
+	var lines [][]struct{ x, y int }
+	seen := map[string]bool{}
+	for _, d := range gridDirections {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				line := make([]struct{ x, y int }, 0, length)
+				for i := 0; i < length; i++ {
+					cx, cy := x+i*d.dx, y+i*d.dy
+					if torus {
+						cx, cy = wrap(cx, width), wrap(cy, height)
+					} else if cx < 0 || cx >= width || cy < 0 || cy >= height {
+						break
+					}
+					line = append(line, struct{ x, y int }{cx, cy})
+				}
+				if len(line) < length {
+					continue
+				}
+				if key := lineKey(line, width); !seen[key] {
+					seen[key] = true
+					lines = append(lines, line)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// lineKey identifies the set of cells in a line, whatever their order.
+func lineKey(line []struct{ x, y int }, width int) string {
+	cells := make([]int, len(line))
+	for i, c := range line {
+		cells[i] = c.y*width + c.x
+	}
+	slices.Sort(cells)
+	return fmt.Sprint(cells)
+}
+
+// wrap returns n reduced into [0, size), wrapping negative values round.
+func wrap(n, size int) int {
+	return ((n % size) + size) % size
+}
+
+// lineOf returns the first of the lines made up entirely of the player's
+// marker, as read by at, or nil if there is none.
+func lineOf(lines [][]struct{ x, y int }, player string, at func(x, y int) string) []struct{ x, y int } {
+	for _, line := range lines {
+		full := true
+		for _, c := range line {
+			if at(c.x, c.y) != player {
+				full = false
+				break
+			}
+		}
+		if full {
+			return line
+		}
+	}
+	return nil
+}
@@ -2,10 +2,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -13,7 +18,177 @@ import (
 
 // main is the entry point of the program.
 func main() {
-	p := tea.NewProgram(initialModel())
+	configPath := flag.String("config", "", "path to a JSON config file (default: user config dir)")
+	themeName := flag.String("theme", "", "colour theme: "+strings.Join(themeNames, ", "))
+	glyphMode := flag.String("glyphs", "", "big glyph markers: auto, on or off")
+	modeName := flag.String("mode", "", "game mode: "+strings.Join(modeNames, ", "))
+	misere := flag.Bool("misere", false, "misère play: completing a line loses")
+	disappearing := flag.Bool("disappearing", false, "classic mode: each player keeps at most three marks")
+	boards := flag.Int("boards", 0, "number of boards in notakto mode (default 3)")
+	computer := flag.Bool("computer", false, "let the computer play second where it can (qubic, notakto)")
+	teams := flag.Bool("teams", false, "two teams of two players, teammates alternating")
+	torus := flag.Bool("torus", false, "classic mode: lines and the cursor wrap round the board edges")
+	gomokuRuleName := flag.String("gomoku-rule", "", "gomoku rule: "+strings.Join(gomokuRuleNames, ", "))
+	firstMoveName := flag.String("first-move", "", "who starts each game: "+strings.Join(firstMovePolicyNames, ", "))
+	firstTo := flag.Int("first-to", 0, "play a match that ends when a side reaches this many wins")
+	bestOf := flag.Int("best-of", 0, "play a match of at most this many games")
+	openingName := flag.String("opening", "", "opening rule: "+strings.Join(openingRuleNames, ", "))
+	shapeName := flag.String("shape", "", "classic board shape: "+strings.Join(boardShapeNames, ", "))
+	obstacles := flag.Int("obstacles", 0, "classic mode: number of random blocked cells")
+	seed := flag.Uint64("seed", 0, "seed for the random blocked cells, the same every game (default new each game)")
+	puzzlesPath := flag.String("puzzles", "", "puzzle mode: file of puzzles to play after the built-in ones")
+	layoutPath := flag.String("layout", "", "classic mode: file drawing the board, . for open cells and # for blocked ones")
+	cols := flag.Int("cols", 0, "columns on the board in gravity mode (default 7)")
+	rows := flag.Int("rows", 0, "rows on the board in gravity mode (default 6)")
+	connect := flag.Int("connect", 0, "markers in a row needed to win in gravity mode (default 4)")
+	moveTime := flag.Duration("move-time", 0, "time allowed per move, e.g. 30s")
+	gameTime := flag.Duration("game-time", 0, "time allowed per player per game, e.g. 2m")
+	increment := flag.Duration("increment", 0, "time added to the game clock after each move, e.g. 5s")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("Alas, there's been an error: %v", err)
+		os.Exit(1)
+	}
+	if *themeName != "" {
+		if _, err := themeByName(*themeName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Theme = *themeName
+	}
+	if *glyphMode != "" {
+		if err := validateGlyphMode(*glyphMode); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Glyphs = *glyphMode
+	}
+	if *modeName != "" {
+		if _, err := parseMode(*modeName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Mode = *modeName
+	}
+	if *misere {
+		cfg.Misere = true
+	}
+	if *disappearing {
+		cfg.Disappearing = true
+	}
+	if *boards != 0 {
+		if err := validateNotaktoBoards(*boards); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Boards = *boards
+	}
+	if *computer {
+		cfg.Computer = true
+	}
+	if *teams {
+		cfg.Teams = true
+	}
+	if *torus {
+		cfg.Torus = true
+	}
+	if *gomokuRuleName != "" {
+		if _, err := parseGomokuRule(*gomokuRuleName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.GomokuRule = *gomokuRuleName
+	}
+	if *firstMoveName != "" {
+		if _, err := parseFirstMovePolicy(*firstMoveName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.FirstMove = *firstMoveName
+	}
+	if *firstTo != 0 || *bestOf != 0 {
+		mc := matchConfig{FirstTo: *firstTo, BestOf: *bestOf}
+		if err := mc.validate(); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Match = mc
+	}
+	if *openingName != "" {
+		if _, err := parseOpeningRule(*openingName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Opening = *openingName
+	}
+	if *shapeName != "" {
+		if _, err := parseBoardShape(*shapeName); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Shape = *shapeName
+	}
+	if *obstacles > 0 {
+		cfg.Obstacles = *obstacles
+	}
+	if *seed != 0 {
+		cfg.Seed = *seed
+	}
+	if *layoutPath != "" {
+		if cfg.layout, err = loadLayout(*layoutPath); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Layout = *layoutPath
+	}
+	if *puzzlesPath != "" {
+		if cfg.puzzles, err = loadPuzzles(*puzzlesPath); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Puzzles = *puzzlesPath
+	}
+	if path, err := defaultStatsPath(); err == nil {
+		// A damaged stats file is left as it is: the session starts from
+		// empty stats and saves none over it.
+		if cfg.stats, err = loadStats(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; stats will not be saved this session\n", err)
+		} else {
+			cfg.statsPath = path
+		}
+	}
+	if *cols != 0 || *rows != 0 || *connect != 0 {
+		gc := gridConfig{Cols: *cols, Rows: *rows, Connect: *connect}
+		if err := gc.validate(); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+		cfg.Grid = gc.withDefaults(cfg.Grid)
+		if err := cfg.Grid.validate(); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+	}
+	if err := cfg.checkModePlayers(); err != nil {
+		fmt.Printf("Alas, there's been an error: %v", err)
+		os.Exit(1)
+	}
+	if err := cfg.checkLayout(); err != nil {
+		fmt.Printf("Alas, there's been an error: %v", err)
+		os.Exit(1)
+	}
+	if *moveTime > 0 || *gameTime > 0 {
+		cfg.timeControl = timeControl{PerMove: *moveTime, PerGame: *gameTime, Increment: *increment}
+	}
+	// Honour https://no-color.org regardless of the chosen theme.
+	if os.Getenv("NO_COLOR") != "" {
+		cfg.Theme = "monochrome"
+		cfg.noColor = true
+	}
+
+	p := tea.NewProgram(newModel(cfg), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
@@ -25,6 +200,7 @@ type gameState int
 const (
 	nameInput gameState = iota
 	gamePlaying
+	matchOver
 )
 
 // model represents the state of our Tic-Tac-Toe game.
@@ -32,21 +208,78 @@ type model struct {
 	board        [3][3]string // Represents the 3x3 game board
 	cursorX      int          // The cursor's X position (column)
 	cursorY      int          // The cursor's Y position (row)
-	player       string       // The current player ("X" or "O")
+	player       string       // The current player's marker ("X", "O", ...)
 	winner       string       // The winner of the game, if any
 	isDraw       bool         // True if the game is a draw
-	player1Name  string
-	player2Name  string
-	player1Score int
-	player2Score int
+	seats        []seat       // The players in turn order, with their scores
+	turn         int          // Index in seats of the player to move
+	starter      int          // Index in seats of the player who started this game, with X
+	games        []gameRecord // Finished games of the match in progress
 	inputs       []textinput.Model
 	focusIndex   int
 	gameState    gameState
 	winningCells []struct{ x, y int }
+	cfg          config     // Settings the game was started with
+	keys         keyMap     // Active key bindings
+	help         help.Model // Renders the help footer from keys
+	theme        theme      // Active colour theme
+	width        int        // Terminal width, 0 until the first tea.WindowSizeMsg
+	height       int        // Terminal height, 0 until the first tea.WindowSizeMsg
+	timeControl  timeControl
+	clock        clock
+	flagged      string // The player who ran out of time, if any
+	misere       bool   // Completing a line loses instead of wins
+	disappearing bool   // Each player keeps at most three marks in modeClassic
+	computer     bool   // Player 2 is played by the computer
+	teams        bool   // Two teams of two share the X and O seats
+	torus        bool   // The classic board wraps round at the edges
+	lineLoser    string // The player who completed a line in misère play, if any
+	mode         gameMode
+	ultimate     ultimateBoard   // Board of boards, used in modeUltimate
+	grid         grid            // Variable-size board, used in modeGravity and modeOrderChaos
+	qubic        qubicBoard      // 4x4x4 cube, used in modeQubic
+	notakto      notaktoBoard    // Shared-X boards, used in modeNotakto
+	vanish       vanishingMarks  // Marks placed in disappearing play
+	quantum      quantumBoard    // Spooky and classical marks, used in modeQuantum
+	cursorZ      int             // The cursor's layer in modeQubic
+	gomokuRule   gomokuRule      // How lines are counted in modeGomoku
+	forbidden    [][]bool        // Points Black may not play, worked out once per move
+	shape        boardShape      // Outline of the classic board
+	opening      openingRule     // Fairness rule for the start of each game
+	firstMove    firstMovePolicy // Who starts each game
+	chosenSeat   int             // The seat picked on the setup screen to start under firstMoveChosen
+	swapped      bool            // The sides were swapped this game
+	extended     bool            // The second player chose to place two more Swap2 stones
+	selected     int             // Cell y*3+x of the piece picked up in modeMorris, or -1
+	armed        string          // The marker the current player places in modeWild and modeOrderChaos
+	number       int             // The number the current player places in modeNumerical
+	moves        int             // Moves made in the current game
+	puzzles      []puzzle        // Built-in and user puzzles, used in modePuzzle
+	puzzle       int             // Index in puzzles of the puzzle being played
+	hints        int             // Hints taken on the current puzzle
+	statsErr     error           // Why the stats could not be saved, if they could not
 }
 
-// initialModel creates the initial state of the game.
+// initialModel creates the initial state of the game with default settings.
 func initialModel() model {
+	return newModel(defaultConfig())
+}
+
+// newModel creates the initial state of the game from the given settings.
+func newModel(cfg config) model {
+	keys := defaultKeyMap()
+	_ = keys.applyOverrides(cfg.Keys) // Already validated by loadConfig
+	keys.Theme.SetEnabled(!cfg.noColor)
+	th, err := themeByName(cfg.Theme)
+	if err != nil {
+		th, _ = themeByName("default")
+	}
+	mode, _ := parseMode(cfg.Mode) // Falls back to classic
+	rule, _ := parseGomokuRule(cfg.GomokuRule)
+	shape, _ := parseBoardShape(cfg.Shape)
+	opening, _ := parseOpeningRule(cfg.Opening)
+	firstMove, _ := parseFirstMovePolicy(cfg.FirstMove)
+
 	m := model{
 		board: [3][3]string{
 			{" ", " ", " "},
@@ -59,27 +292,65 @@ func initialModel() model {
 		winner:       "",
 		isDraw:       false,
 		gameState:    nameInput,
-		inputs:       make([]textinput.Model, 2),
+		inputs:       make([]textinput.Model, max(minPlayers, len(cfg.Players))),
 		focusIndex:   0,
 		winningCells: []struct{ x, y int }{},
+		cfg:          cfg,
+		keys:         keys,
+		help:         help.New(),
+		theme:        th,
+		timeControl:  cfg.timeControl,
+		clock:        newClock(cfg.timeControl),
+		misere:       cfg.Misere,
+		disappearing: cfg.Disappearing,
+		computer:     cfg.Computer,
+		teams:        cfg.Teams,
+		torus:        cfg.Torus,
+		mode:         mode,
+		ultimate:     newUltimateBoard(),
+		qubic:        newQubicBoard(),
+		notakto:      newNotaktoBoard(cfg.Boards),
+		quantum:      newQuantumBoard(),
+		gomokuRule:   rule,
+		shape:        shape,
+		opening:      opening,
+		firstMove:    firstMove,
+		selected:     -1,
+		armed:        "X",
+		number:       1,
+		puzzles:      append(slices.Clone(builtinPuzzles), cfg.puzzles...),
+	}
+	if m.teams {
+		m.inputs = make([]textinput.Model, 2*teamSize)
 	}
-
-	var t textinput.Model
 	for i := range m.inputs {
-		t = textinput.New()
-		t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		t.CharLimit = 32
-		t.Placeholder = fmt.Sprintf("Player %d", i+1)
-		if i == 0 {
-			t.Focus()
-			t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		}
-		m.inputs[i] = t
+		m.inputs[i] = m.newInput(i)
 	}
+	m.seats = newSeats(len(m.inputs), cfg.Players, m.teams)
+	m.theme = m.theme.withPlayerColors(cfg.Players, m.seats)
+	m.grid = m.modeGrid()
+	m.keys.applyMode(m.mode, m.sides())
+	m.keys.applySetup(len(m.inputs), mode, m.teams)
 
 	return m
 }
 
+// newInput returns the name input for player i, focused for the first.
+func (m model) newInput(i int) textinput.Model {
+	t := textinput.New()
+	t.Cursor.Style = m.theme.promptStyle()
+	t.CharLimit = 32
+	t.Placeholder = fmt.Sprintf("Player %d", i+1)
+	if i%2 == 1 && m.computerPlays() {
+		t.Placeholder = computerName
+	}
+	if i == 0 {
+		t.Focus()
+		t.PromptStyle = m.theme.promptStyle()
+	}
+	return t
+}
+
 func (m model) resetGame() model {
 	m.board = [3][3]string{
 		{" ", " ", " "},
@@ -89,12 +360,99 @@ func (m model) resetGame() model {
 	m.cursorX = 0
 	m.cursorY = 0
 	m.player = "X"
+	if m.mode == modePuzzle {
+		m.starter = 0 // The solver plays X against the computer
+	}
+	m.turn = m.starter
+	m.resetMarkers()
+	m.theme = m.playerTheme(m.theme.name)
+	m.swapped = false
+	m.extended = false
 	m.winner = ""
 	m.isDraw = false
 	m.winningCells = []struct{ x, y int }{}
+	m.flagged = ""
+	m.lineLoser = ""
+	m.clock = newClock(m.timeControl)
+	m.ultimate = newUltimateBoard()
+	m.grid = m.modeGrid()
+	if m.openingRule() == openingBalanced {
+		m.placeBalancedOpening()
+	}
+	m.qubic = newQubicBoard()
+	m.notakto = newNotaktoBoard(m.cfg.Boards)
+	m.vanish = vanishingMarks{}
+	m.quantum = newQuantumBoard()
+	m.cursorZ = 0
+	m.selected = -1
+	m.armed = "X"
+	m.number = 1
+	m.moves = 0
+	m.hints = 0
+	m.statsErr = nil
+	m.forbidden = m.forbiddenPoints()
 	return m
 }
 
+// newGame resets the board and starts the clock for the next game. Once
+// the last game of a match has finished it shows the match summary instead.
+func (m model) newGame() (model, tea.Cmd) {
+	if m.winner != "" || m.isDraw {
+		if m.cfg.Match.enabled() {
+			over := m.matchOver()
+			m.games = m.matchGames()
+			if over {
+				m.gameState = matchOver
+				return m, nil
+			}
+		}
+		m.starter = m.nextStarter()
+		if m.puzzleSolved() {
+			m.puzzle = m.nextPuzzle()
+		}
+	}
+	m = m.resetGame()
+	cmd := m.clock.start(time.Now())
+	return m, tea.Batch(cmd, m.computerTurn())
+}
+
+// recordWin ends the game in favour of player and updates the score.
+func (m *model) recordWin(player string) {
+	m.winner = player
+	m.clock.stop()
+	m.seats = slices.Clone(m.seats) // Leave earlier copies of the model their scores
+	for i := range m.seats {
+		if m.seats[i].marker == player {
+			m.seats[i].score++ // Both members of a team
+		}
+	}
+	m.recordStats()
+}
+
+// timeOut ends the game because the current player ran out of time.
+func (m *model) timeOut() {
+	m.flagged = m.player
+	m.forbidden = nil
+	if m.mode == modePuzzle {
+		m.failPuzzle()
+		return
+	}
+	m.recordWin(opponent(m.player))
+}
+
+// playerName returns the name of the player using the given marker.
+func (m model) playerName(player string) string {
+	return m.seats[m.seatOf(player)].name
+}
+
+// opponent returns the other player's marker.
+func opponent(player string) string {
+	if player == "X" {
+		return "O"
+	}
+	return "X"
+}
+
 // Init is called once when the program starts.
 func (m model) Init() tea.Cmd {
 	return textinput.Blink
@@ -104,10 +462,14 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		if key.Matches(msg, m.keys.Quit) {
 			return m, tea.Quit
 		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
 	}
 
 	switch m.gameState {
@@ -115,6 +477,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updateNameInput(msg, m)
 	case gamePlaying:
 		return updateGamePlaying(msg, m)
+	case matchOver:
+		return updateMatchOver(msg, m)
 	default:
 		return m, nil
 	}
@@ -123,46 +487,84 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func updateNameInput(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, m.keys.Submit):
 			if m.focusIndex == len(m.inputs)-1 {
-				m.player1Name = m.inputs[0].Value()
-				m.player2Name = m.inputs[1].Value()
+				m.seats = newSeats(len(m.inputs), m.cfg.Players, m.teams)
+				for i := range m.seats {
+					m.seats[i].name = m.inputs[i].Value()
+					if m.computerPlays() && i%2 == 1 && m.seats[i].name == "" {
+						m.seats[i].name = computerName
+					}
+				}
+				if m.multiplayer() {
+					m.timeControl = timeControl{} // The clock has room for two
+				}
+				m.keys.applyMode(m.mode, m.sides())
+				m.starter = m.firstStarter()
 				m.gameState = gamePlaying
-				return m, nil
+				return m.newGame()
 			}
 			m.focusIndex++
 			for i := 0; i <= len(m.inputs)-1; i++ {
 				if i == m.focusIndex {
 					m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+					m.inputs[i].PromptStyle = m.theme.promptStyle()
 				} else {
 					m.inputs[i].Blur()
 					m.inputs[i].PromptStyle = lipgloss.NewStyle()
 				}
 			}
 			return m, nil
-		case "up":
+		case key.Matches(msg, m.keys.AddPlayer):
+			if len(m.inputs) < m.mode.playerLimit() {
+				m.inputs = append(m.inputs, m.newInput(len(m.inputs)))
+				m.keys.applySetup(len(m.inputs), m.mode, m.teams)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.RemovePlayer):
+			if len(m.inputs) > minPlayers {
+				m.inputs = m.inputs[:len(m.inputs)-1]
+				if m.focusIndex >= len(m.inputs) {
+					m.focusIndex = len(m.inputs) - 1
+					m.inputs[m.focusIndex].Focus()
+					m.inputs[m.focusIndex].PromptStyle = m.theme.promptStyle()
+				}
+				m.keys.applySetup(len(m.inputs), m.mode, m.teams)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.FirstMove):
+			m.cycleFirstMove()
+			return m, nil
+		case key.Matches(msg, m.keys.Symbol):
+			m.cycleSymbol(m.focusIndex)
+			return m, nil
+		case key.Matches(msg, m.keys.Teams):
+			m.toggleTeams()
+			m.keys.applyMode(m.mode, m.sides())
+			m.keys.applySetup(len(m.inputs), m.mode, m.teams)
+			return m, nil
+		case key.Matches(msg, m.keys.PrevField):
 			if m.focusIndex > 0 {
 				m.focusIndex--
 			}
 			for i := 0; i <= len(m.inputs)-1; i++ {
 				if i == m.focusIndex {
 					m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+					m.inputs[i].PromptStyle = m.theme.promptStyle()
 				} else {
 					m.inputs[i].Blur()
 					m.inputs[i].PromptStyle = lipgloss.NewStyle()
 				}
 			}
-		case "down":
+		case key.Matches(msg, m.keys.NextField):
 			if m.focusIndex < len(m.inputs)-1 {
 				m.focusIndex++
 			}
 			for i := 0; i <= len(m.inputs)-1; i++ {
 				if i == m.focusIndex {
 					m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+					m.inputs[i].PromptStyle = m.theme.promptStyle()
 				} else {
 					m.inputs[i].Blur()
 					m.inputs[i].PromptStyle = lipgloss.NewStyle()
@@ -185,63 +587,192 @@ func (m *model) updateInputs(msg tea.Msg) tea.Cmd {
 
 func updateGamePlaying(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tickMsg:
+		if !m.clock.running || msg.id != m.clock.id {
+			return m, nil // A tick from a stopped or earlier clock
+		}
+		if m.clock.elapse(m.player, msg.t) {
+			m.timeOut()
+			return m, nil
+		}
+		return m, m.clock.tick()
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+r":
-			return initialModel(), nil
-		case "r":
-			return m.resetGame(), nil
-		case "up", "k":
-			if m.cursorY > 0 {
-				m.cursorY--
+		switch {
+		case key.Matches(msg, m.keys.NewSession):
+			m.abandonPuzzle()
+			fresh := newModel(m.cfg)
+			fresh.width, fresh.height = m.width, m.height
+			fresh.help.Width = m.width
+			return fresh, nil
+		case key.Matches(msg, m.keys.Reset):
+			m.abandonPuzzle()
+			return m.newGame()
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, m.keys.Theme):
+			m.cfg.Theme = nextThemeName(m.theme.name)
+			m.theme = m.playerTheme(m.cfg.Theme)
+		case key.Matches(msg, m.keys.TimeControl):
+			// The time control can only change before the first move.
+			if m.moves == 0 && m.winner == "" {
+				m.abandonPuzzle()
+				m.timeControl = nextTimePreset(m.timeControl)
+				m.cfg.timeControl = m.timeControl
+				return m.newGame()
+			}
+		case key.Matches(msg, m.keys.Misere):
+			// Flipping the objective mid-game would be unfair.
+			if m.moves == 0 && m.winner == "" {
+				m.misere = !m.misere
+				m.cfg.Misere = m.misere
+			}
+		case key.Matches(msg, m.keys.Disappear):
+			if m.moves == 0 && m.winner == "" {
+				m.disappearing = !m.disappearing
+				m.cfg.Disappearing = m.disappearing
+			}
+		case key.Matches(msg, m.keys.Wrap):
+			// The board changes shape, so a new game starts.
+			if m.moves == 0 && m.winner == "" {
+				m.torus = !m.torus
+				m.cfg.Torus = m.torus
+				return m.newGame()
+			}
+		case key.Matches(msg, m.keys.Mode):
+			// Likewise the mode, as it replaces the board.
+			if m.moves == 0 && m.winner == "" {
+				m.abandonPuzzle()
+				m.mode = m.mode.next()
+				for m.mode.playerLimit() < m.sides() {
+					m.mode = m.mode.next()
+				}
+				m.cfg.Mode = m.mode.String()
+				m.keys.applyMode(m.mode, m.sides())
+				return m.newGame()
+			}
+		case key.Matches(msg, m.keys.GomokuRule):
+			if m.moves == 0 && m.winner == "" {
+				m.gomokuRule = m.gomokuRule.next()
+				m.cfg.GomokuRule = m.gomokuRule.String()
+				m.forbidden = m.forbiddenPoints()
+			}
+		case key.Matches(msg, m.keys.Opening):
+			// A balanced opening puts stones on the board, so a new game
+			// starts.
+			if m.moves == 0 && m.winner == "" {
+				m.opening = m.mode.nextOpening(m.opening)
+				m.cfg.Opening = m.opening.String()
+				return m.newGame()
+			}
+		case key.Matches(msg, m.keys.Hint):
+			if m.mode == modePuzzle && m.winner == "" && !m.computerToMove() {
+				m.takeHint()
+			}
+		case key.Matches(msg, m.keys.NextPuzzle):
+			// A solved puzzle moves on by itself.
+			m.abandonPuzzle()
+			if !m.puzzleSolved() {
+				m.puzzle = m.nextPuzzle()
+			}
+			return m.newGame()
+		case key.Matches(msg, m.keys.Swap):
+			if m.canSwap() {
+				m.swapSides()
+			}
+		case key.Matches(msg, m.keys.Extend):
+			if m.canExtend() {
+				m.extend()
 			}
-		case "down", "j":
-			if m.cursorY < 2 {
-				m.cursorY++
+		case key.Matches(msg, m.keys.Marker):
+			if m.winner == "" && !m.isDraw {
+				m.armed = opponent(m.armed)
 			}
-		case "left", "h":
-			if m.cursorX > 0 {
-				m.cursorX--
+		case key.Matches(msg, m.keys.Number):
+			if n, ok := numberKey(msg.String()); ok && m.winner == "" && !m.isDraw {
+				m.pickNumber(n)
 			}
-		case "right", "l":
-			if m.cursorX < 2 {
-				m.cursorX++
+		case key.Matches(msg, m.keys.Up):
+			m.moveCursor(0, -1)
+		case key.Matches(msg, m.keys.Down):
+			m.moveCursor(0, 1)
+		case key.Matches(msg, m.keys.Left):
+			m.moveCursor(-1, 0)
+		case key.Matches(msg, m.keys.Right):
+			m.moveCursor(1, 0)
+		case key.Matches(msg, m.keys.PrevLayer):
+			if m.cursorZ > 0 {
+				m.cursorZ--
 			}
-		case "enter", " ":
+		case key.Matches(msg, m.keys.NextLayer):
+			if m.cursorZ < qubicSize-1 {
+				m.cursorZ++
+			}
+		case key.Matches(msg, m.keys.Place):
 			if m.winner != "" || m.isDraw {
-				return m.resetGame(), nil
+				return m.newGame()
 			}
 
-			if m.board[m.cursorY][m.cursorX] == " " {
-				m.board[m.cursorY][m.cursorX] = m.player
-				won, cells := checkWinner(m.board, m.player)
-				if won {
-					m.winner = m.player
-					m.winningCells = cells
-					if m.player == "X" {
-						m.player1Score++
-					} else {
-						m.player2Score++
-					}
-				} else if checkDraw(m.board) {
-					m.isDraw = true
-				} else {
-					if m.player == "X" {
-						m.player = "O"
-					} else {
-						m.player = "X"
-					}
-				}
+			if m.computerToMove() {
+				break // Wait for the computer
+			}
+			// Moving a piece takes two presses: one to pick it up and one
+			// to put it down.
+			if m.mode == modeMorris && m.selectMorrisPiece() {
+				break
 			}
+			return m.playMove()
+		}
+	case computerMoveMsg:
+		if m.computerToMove() && m.chooseComputerMove() {
+			return m.playMove()
 		}
 	}
 	return m, nil
 }
 
+// playMove plays the current player's marker at the cursor and moves the
+// game on: ending it on a win, loss or draw, or passing the turn.
+func (m model) playMove() (model, tea.Cmd) {
+	if m.clock.running && m.clock.elapse(m.player, time.Now()) {
+		m.timeOut()
+		return m, nil
+	}
+
+	result := m.placeMarker()
+	switch result {
+	case moveWon, moveLost:
+		m.moves++
+		// Misère play turns a win into a loss and vice versa.
+		if (result == moveWon) != m.misereRules() {
+			m.recordWin(m.player)
+		} else {
+			m.lineLoser = m.player
+			m.recordWin(opponent(m.player))
+		}
+	case moveFailed:
+		m.moves++
+		m.failPuzzle()
+	case moveDrawn:
+		m.moves++
+		m.isDraw = true
+		m.clock.stop()
+		m.recordStats()
+	case moveMade:
+		m.moves++
+		m.clock.moved(m.player)
+		m.nextTurn()
+	}
+	m.forbidden = m.forbiddenPoints()
+	return m, m.computerTurn()
+}
+
 // View renders the UI.
 func (m model) View() string {
-	if m.gameState == nameInput {
+	switch m.gameState {
+	case nameInput:
 		return viewNameInput(m)
+	case matchOver:
+		return viewMatchOver(m)
 	}
 	return viewGamePlaying(m)
 }
@@ -250,109 +781,243 @@ func viewNameInput(m model) string {
 	var b strings.Builder
 	b.WriteString("Enter Player Names\n\n")
 	for i := range m.inputs {
+		marker := seatMarker(i, m.teams)
+		label := m.setupSymbol(i)
+		if m.teams {
+			label = m.seatLabel(marker)
+		}
+		b.WriteString(m.theme.markerStyle(marker).Render(label) + " ")
 		b.WriteString(m.inputs[i].View())
 		if i < len(m.inputs)-1 {
 			b.WriteRune('\n')
 		}
 	}
-	b.WriteString("\n\nPress Enter to continue.")
-	return b.String()
+	b.WriteString("\n\n" + m.firstMoveStatus())
+	if m.timeControl.enabled() && !m.teams && len(m.inputs) > minPlayers {
+		b.WriteString("\nTime control: off, as the clock is for two players")
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.help.ShortHelpView(m.keys.setupHelp()))
+	return m.place(b.String())
 }
 
 func viewGamePlaying(m model) string {
-	s := "Tic-Tac-Toe\n\n"
-	s += fmt.Sprintf("Score: %s (X) %d - %d %s (O)\n\n", m.player1Name, m.player1Score, m.player2Score, m.player2Name)
+	header := m.mode.title()
+	if m.mode == modeGomoku {
+		header += fmt.Sprintf(" (%s)", m.gomokuRule.title())
+	}
+	if m.misereRules() {
+		header += " (Misère)"
+	}
+	if m.disappearingRules() {
+		header += " (Disappearing)"
+	}
+	if m.wraps() {
+		header += " (Torus)"
+	}
+	if rule := m.openingRule(); rule != openingNone {
+		header += fmt.Sprintf(" (%s)", rule.title())
+	}
+	if m.hasObstacles() && m.shape != shapeSquare {
+		header += fmt.Sprintf(" (%s)", m.shape.title())
+	}
+	header += "\n\n"
+	header += viewScores(m)
+	if m.cfg.Match.enabled() {
+		header += "\n" + matchStatus(m)
+	}
+	if m.mode == modePuzzle {
+		header += "\n" + puzzleStatus(m)
+	}
+	if m.timeControl.enabled() {
+		header += "\n" + viewClock(m)
+	}
 
-	var boardView string
-	var rows []string
+	again := fmt.Sprintf("(Press %s to play again)", m.keys.Place.Help().Key)
+	if m.matchOver() {
+		again = fmt.Sprintf("(Press %s for the match summary)", m.keys.Place.Help().Key)
+	}
+	var status string
+	if m.mode == modePuzzle && (m.winner != "" || m.isDraw) {
+		status = puzzleResult(m)
+	} else if m.winner != "" {
+		status = fmt.Sprintf("%s wins! %s", m.sideName(m.winner), again)
+		if m.flagged != "" {
+			status = fmt.Sprintf("%s ran out of time! %s", m.sideName(m.flagged), status)
+		}
+		if m.mode == modeQuantum && m.flagged == "" {
+			status = fmt.Sprintf("%s completed a line first, on move %d! %s", m.playerName(m.winner), m.quantum.winningMove, status)
+		} else if m.lineLoser != "" && m.mode == modeNotakto {
+			status = fmt.Sprintf("%s killed the last board and loses! %s", m.playerName(m.lineLoser), status)
+		} else if m.lineLoser != "" {
+			status = fmt.Sprintf("%s completed a line and loses! %s", m.seatName(m.lineLoser), status)
+		} else if m.mode == modeOrderChaos && m.flagged == "" && m.grid.full() {
+			status = fmt.Sprintf("The board is full with no line of %d! %s", orderChaosLength, status)
+		}
+	} else if m.isDraw {
+		status = "It's a draw! " + again
+		if m.disappearingRules() && m.moves >= disappearingMoveLimit {
+			status = fmt.Sprintf("No winner after %d moves. %s", disappearingMoveLimit, status)
+		} else if m.disappearingRules() {
+			status = fmt.Sprintf("The position repeated %d times. %s", disappearingRepeats, status)
+		}
+	} else {
+		status = fmt.Sprintf("%s's turn", m.seats[m.turn].name)
+		if label := m.seatLabel(m.player); label != "" {
+			status += fmt.Sprintf(" (%s)", label)
+		}
+		if m.keys.Marker.Enabled() {
+			status += "\n" + markerHint(m)
+		}
+		if m.mode == modeUltimate {
+			status += "\n" + ultimateHint(m)
+		}
+		if m.disappearingRules() {
+			status += "\n" + disappearingHint(m)
+		}
+		if m.mode == modeMorris {
+			status += "\n" + morrisHint(m)
+		}
+		if m.mode == modeNumerical {
+			status += "\n" + numericalHint(m)
+		}
+		if m.mode == modeQuantum {
+			status += "\n" + quantumHint(m)
+		}
+		if m.mode == modePuzzle {
+			status += "\n" + puzzleHint(m)
+		}
+		if hint := openingHint(m); hint != "" {
+			status += "\n" + hint
+		}
+	}
+	if (m.winner != "" || m.isDraw) && m.mode != modePuzzle {
+		status += "\n" + viewRecord(m)
+	}
+	footer := status + "\n\n" + m.help.View(m.keys)
 
+	// A blank line separates the board from the header and the footer, and
+	// another ends the view. They are dropped when the board would not fit
+	// otherwise, as for a 15x15 Gomoku board with a clock in 80x24.
+	gap, end := "\n\n", "\n"
+	reserved := lipgloss.Height(header) + lipgloss.Height(footer)
+	board, needWidth, needHeight, ok := viewBoard(m, reserved+3)
+	if !ok {
+		gap, end = "\n", ""
+		board, needWidth, needHeight, ok = viewBoard(m, reserved)
+	}
+	if !ok {
+		return viewTooSmall(m, needWidth, needHeight)
+	}
+
+	return m.place(header + gap + board + gap + footer + end)
+}
+
+// viewBoard draws the board for the active mode. It returns the size needed
+// and false when the board does not fit in the terminal.
+func viewBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	switch m.mode {
+	case modeUltimate:
+		return viewUltimateBoard(m, reservedLines)
+	case modeGravity:
+		return viewGravityBoard(m, reservedLines)
+	case modeOrderChaos, modeGomoku, modePuzzle:
+		return viewGrid(m, reservedLines)
+	case modeClassic:
+		if m.classicGrid() {
+			return viewGrid(m, reservedLines)
+		}
+	case modeQubic:
+		return viewQubicBoard(m, reservedLines)
+	case modeNotakto:
+		return viewNotaktoBoard(m, reservedLines)
+	case modeQuantum:
+		return viewQuantumBoard(m, reservedLines)
+	}
+
+	lay, ok := chooseLayout(m.cfg.Glyphs, m.width, m.height, 3, 3, reservedLines)
+	w, h := lay.boardSize(3, 3)
+	if !ok {
+		return "", w, h + reservedLines, false
+	}
+	return renderBoard(m, lay), w, h + reservedLines, true
+}
+
+// renderBoard draws the board using the given cell layout.
+func renderBoard(m model, lay cellLayout) string {
+	var rows []string
 	for i := 0; i < 3; i++ {
 		var rowItems []string
 		for j := 0; j < 3; j++ {
-			cell := m.board[i][j]
-			style := lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), true).
-				BorderForeground(lipgloss.Color("63")).
-				Width(5).
-				Height(1).
-				Align(lipgloss.Center, lipgloss.Center)
-
-			if m.cursorY == i && m.cursorX == j {
-				style = style.Copy().BorderForeground(lipgloss.Color("205"))
-			}
-
-			for _, winningCell := range m.winningCells {
-				if winningCell.x == j && winningCell.y == i {
-					style = style.Copy().Foreground(lipgloss.Color("196"))
-				}
-			}
-
-			var renderedCell string
-			if cell == "X" {
-				renderedCell = style.Copy().Foreground(lipgloss.Color("202")).Render(cell)
-			} else if cell == "O" {
-				renderedCell = style.Copy().Foreground(lipgloss.Color("39")).Render(cell)
-			} else {
-				renderedCell = style.Render(cell)
+			isCursor := m.cursorY == i && m.cursorX == j
+			cell, cm := m.board[i][j], m
+			switch {
+			case m.vanishesNext(j, i):
+				cm.theme = m.theme.faded()
+			case m.mode == modeMorris && m.selected == i*3+j:
+				cm.theme = m.theme.highlighted()
+			case m.mode == modeMorris && m.morrisDestination(j, i):
+				cell = morrisMark(m.theme)
 			}
-			rowItems = append(rowItems, renderedCell)
+			rowItems = append(rowItems, renderCell(cm, lay, cell, isCursor, m.isWinningCell(j, i)))
 		}
 		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
 	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
 
-	boardView = lipgloss.JoinVertical(lipgloss.Left, rows...)
-	s += boardView
-
-	if m.winner != "" {
-		winnerName := m.player1Name
-		if m.winner == "O" {
-			winnerName = m.player2Name
+// isWinningCell reports whether the cell at column x, row y is part of the
+// winning line.
+func (m model) isWinningCell(x, y int) bool {
+	for _, winningCell := range m.winningCells {
+		if winningCell.x == x && winningCell.y == y {
+			return true
 		}
-		s += fmt.Sprintf("\n\n%s wins! (Press Enter to play again)", winnerName)
-	} else if m.isDraw {
-		s += "\n\nIt's a draw! (Press Enter to play again)"
-	} else {
-		playerName := m.player1Name
-		if m.player == "O" {
-			playerName = m.player2Name
-		}
-		s += fmt.Sprintf("\n\n%s's turn (%s)", playerName, m.player)
 	}
-
-	s += "\n\nUse arrow keys or h/j/k/l to move.\n"
-	s += "Press Enter or Space to place your marker.\n"
-	s += "Press 'r' to reset the game.\n"
-	s += "Press 'ctrl+r' to reset scores and names.\n"
-	s += "Press 'q' or 'ctrl+c' to quit.\n"
-
-	return s
+	return false
 }
 
-// checkWinner checks if the given player has won the game.
-func checkWinner(board [3][3]string, player string) (bool, []struct{ x, y int }) {
-	// Check rows
-	for i := 0; i < 3; i++ {
-		if board[i][0] == player && board[i][1] == player && board[i][2] == player {
-			return true, []struct{ x, y int }{{0, i}, {1, i}, {2, i}}
+// renderCell draws a cell holding the given marker.
+func renderCell(m model, lay cellLayout, cell string, isCursor, isWinning bool) string {
+	style := lipgloss.NewStyle().
+		Width(lay.width).
+		Height(lay.height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	if lay.bordered {
+		style = style.Border(lipgloss.NormalBorder(), true).BorderForeground(m.theme.border)
+		if isCursor {
+			style = style.Border(m.theme.cursorBorder, true).BorderForeground(m.theme.cursor)
 		}
 	}
 
-	// Check columns
-	for i := 0; i < 3; i++ {
-		if board[0][i] == player && board[1][i] == player && board[2][i] == player {
-			return true, []struct{ x, y int }{{i, 0}, {i, 1}, {i, 2}}
-		}
+	if isWinning {
+		style = style.Inherit(m.theme.win)
 	}
 
-	// Check diagonals
-	if board[0][0] == player && board[1][1] == player && board[2][2] == player {
-		return true, []struct{ x, y int }{{0, 0}, {1, 1}, {2, 2}}
+	style = style.Inherit(m.theme.markerStyle(cell))
+	cell = m.symbol(cell) // A player's own symbol has no block art of its own
+
+	if lay.glyphs {
+		return style.Render(glyph(cell))
 	}
-	if board[0][2] == player && board[1][1] == player && board[2][0] == player {
-		return true, []struct{ x, y int }{{2, 0}, {1, 1}, {0, 2}}
+
+	// Without borders empty cells get a dot so the grid stays visible, and
+	// the cursor is shown as brackets around the cell.
+	if !lay.bordered && cell == " " {
+		cell = "·"
+	}
+	if !lay.bordered && isCursor {
+		bracket := lipgloss.NewStyle().Foreground(m.theme.cursor)
+		return bracket.Render("[") + style.Width(lay.width-2).Render(cell) + bracket.Render("]")
 	}
+	return style.Render(cell)
+}
 
-	return false, nil
+// checkWinner checks if the given player has won the game.
+func checkWinner(board [3][3]string, player string) (bool, []struct{ x, y int }) {
+	line := lineOf(classicLines, player, func(x, y int) string { return board[y][x] })
+	return line != nil, line
 }
 
 // checkDraw checks if the game is a draw.
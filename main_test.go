@@ -2,12 +2,71 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// playingModel returns a model in the playing state with the given
+// settings, its seats named P1, P2 and so on.
+func playingModel(cfg config) model {
+	m := newModel(cfg)
+	m.gameState = gamePlaying
+	for i := range m.seats {
+		m.seats[i].name = fmt.Sprintf("P%d", i+1)
+	}
+	return m
+}
+
+// placeAt moves the cursor to (x, y) and presses enter.
+func placeAt(m model, x, y int) model {
+	m.cursorX, m.cursorY = x, y
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updatedModel.(model)
+}
+
+// dropIn moves the cursor to column x and drops a marker.
+func dropIn(m model, x int) model {
+	m.cursorX = x
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updatedModel.(model)
+}
+
+// pick presses the digit key for n.
+func pick(m model, n int) model {
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{rune('0' + n)}})
+	return updatedModel.(model)
+}
+
+// spooky makes a spooky move in cells a and b, given as y*3+x.
+func spooky(m model, a, b int) model {
+	m = placeAt(m, a%3, a/3)
+	return placeAt(m, b%3, b/3)
+}
+
+// setupModel enters the given names on the name input screen, adding rows
+// as needed, and starts the game.
+func setupModel(cfg config, names ...string) model {
+	m := newModel(cfg)
+	for len(m.inputs) < len(names) {
+		m.inputs = append(m.inputs, m.newInput(len(m.inputs)))
+	}
+	for i, name := range names {
+		m.inputs[i].SetValue(name)
+	}
+	m.focusIndex = len(m.inputs) - 1
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updatedModel.(model)
+}
+
+// pressKey sends a single rune key to the model.
+func pressKey(m model, r rune) model {
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	return updatedModel.(model)
+}
+
 // TestInitialModel verifies that the game starts with the correct default state.
 func TestInitialModel(t *testing.T) {
 	m := initialModel()
@@ -406,8 +465,8 @@ func TestView(t *testing.T) {
 	t.Run("Game playing view", func(t *testing.T) {
 		m := initialModel()
 		m.gameState = gamePlaying
-		m.player1Name = "P1"
-		m.player2Name = "P2"
+		m.seats[0].name = "P1"
+		m.seats[1].name = "P2"
 		view := m.View()
 
 		if !contains(view, "Tic-Tac-Toe") {
@@ -421,8 +480,8 @@ func TestView(t *testing.T) {
 	t.Run("Win view", func(t *testing.T) {
 		m := initialModel()
 		m.gameState = gamePlaying
-		m.player1Name = "P1"
-		m.player2Name = "P2"
+		m.seats[0].name = "P1"
+		m.seats[1].name = "P2"
 		m.winner = "O"
 		view := m.View()
 
@@ -465,3 +524,70 @@ func TestView(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+// TestUpdateHelpToggle checks that the help key switches between short and full help.
+func TestUpdateHelpToggle(t *testing.T) {
+	m := playingModel(config{})
+
+	if contains(m.View(), "reset scores and names") {
+		t.Errorf("Short help should not list the new session binding")
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updatedModel.(model)
+	if !m.help.ShowAll {
+		t.Fatal("Expected full help to be shown after pressing '?'")
+	}
+	if !contains(m.View(), "reset scores and names") {
+		t.Errorf("Full help does not list the new session binding")
+	}
+}
+
+// TestUpdateMisere checks that completing a line loses in misère play.
+func TestUpdateMisere(t *testing.T) {
+	m := playingModel(config{Misere: true})
+	m.board = [3][3]string{
+		{"X", "X", " "},
+		{"O", "O", " "},
+		{"O", " ", " "},
+	}
+	m.cursorX = 2
+	m.cursorY = 0
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+
+	if m.winner != "O" || m.lineLoser != "X" {
+		t.Errorf("Expected X to lose by completing a line, got winner %q loser %q", m.winner, m.lineLoser)
+	}
+	if m.seats[0].score != 0 || m.seats[1].score != 1 {
+		t.Errorf("Expected the score to be 0 - 1, got %d - %d", m.seats[0].score, m.seats[1].score)
+	}
+	view := m.View()
+	if !contains(view, "P1 (X) completed a line and loses! P2 wins!") {
+		t.Errorf("View does not report the misère loss")
+	}
+	if !contains(view, "(Misère)") {
+		t.Errorf("View does not show that misère play is on")
+	}
+}
+
+// TestUpdateMisereToggle checks that misère play can only be toggled before the first move.
+func TestUpdateMisereToggle(t *testing.T) {
+	m := playingModel(config{})
+	press := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}}
+
+	updatedModel, _ := m.Update(press)
+	m = updatedModel.(model)
+	if !m.misere {
+		t.Fatal("Expected misère play to be on after pressing 'v'")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(press)
+	m = updatedModel.(model)
+	if !m.misere {
+		t.Error("Expected misère play to stay on after a move")
+	}
+}
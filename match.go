@@ -0,0 +1,230 @@
+// match.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxMatchGames caps the length of a match, so a typo cannot start one that
+// never ends.
+const maxMatchGames = 99
+
+// matchConfig sets up match play: a series of games that ends when a side
+// reaches FirstTo wins, or after BestOf games once a side cannot be caught.
+// At most one of the two is set; with neither, games go on without end.
+type matchConfig struct {
+	FirstTo int `json:"first_to"`
+	BestOf  int `json:"best_of"`
+}
+
+// validate checks that the match length is sensible.
+func (mc matchConfig) validate() error {
+	if mc.FirstTo != 0 && mc.BestOf != 0 {
+		return fmt.Errorf("a match is either first to %d or best of %d, not both", mc.FirstTo, mc.BestOf)
+	}
+	for _, f := range []struct {
+		name  string
+		value int
+	}{{"first_to", mc.FirstTo}, {"best_of", mc.BestOf}} {
+		if f.value < 0 || f.value > maxMatchGames {
+			return fmt.Errorf("match %s must be between 1 and %d, got %d", f.name, maxMatchGames, f.value)
+		}
+	}
+	return nil
+}
+
+// enabled reports whether games are played as a match.
+func (mc matchConfig) enabled() bool {
+	return mc.FirstTo > 0 || mc.BestOf > 0
+}
+
+// title describes the match, e.g. "first to 5".
+func (mc matchConfig) title() string {
+	if mc.FirstTo > 0 {
+		return fmt.Sprintf("first to %d", mc.FirstTo)
+	}
+	return fmt.Sprintf("best of %d", mc.BestOf)
+}
+
+// gameRecord is the result of one finished game of a match.
+type gameRecord struct {
+	Result string `json:"result"` // Who won and how, e.g. "Ann won", or "Draw"
+	Moves  int    `json:"moves"`  // Moves made in the game
+}
+
+// matchRecord is a finished match, as kept in the stats file.
+type matchRecord struct {
+	Players []string     `json:"players"`
+	Format  string       `json:"format"` // e.g. "first to 5"
+	Games   []gameRecord `json:"games"`
+	Winner  string       `json:"winner,omitempty"` // The winning side; none if drawn
+	Score   string       `json:"score"`            // Highest first, e.g. "3-1"
+}
+
+// gameResult records the finished game.
+func (m model) gameResult() gameRecord {
+	result := "Draw"
+	switch {
+	case m.flagged != "":
+		result = m.sideName(m.winner) + " won on time"
+	case m.winner != "":
+		result = m.sideName(m.winner) + " won"
+	}
+	return gameRecord{Result: result, Moves: m.moves}
+}
+
+// matchGames returns the games of the match so far, including the current
+// game once it has finished.
+func (m model) matchGames() []gameRecord {
+	if m.winner == "" && !m.isDraw {
+		return m.games
+	}
+	return append(slices.Clip(m.games), m.gameResult())
+}
+
+// sideSeats returns a seat of each side, in seat order: the first two seats
+// in a team game, as teammates sit opposite each other, and otherwise every
+// seat.
+func (m model) sideSeats() []seat {
+	return m.seats[:m.sides()]
+}
+
+// matchLeader returns the index in sideSeats of the side with the most wins,
+// and whether it is the only side with that many.
+func (m model) matchLeader() (int, bool) {
+	sides := m.sideSeats()
+	leader, alone := 0, true
+	for i, s := range sides[1:] {
+		switch {
+		case s.score > sides[leader].score:
+			leader, alone = i+1, true
+		case s.score == sides[leader].score:
+			alone = false
+		}
+	}
+	return leader, alone
+}
+
+// matchWinner returns the name of the side that won the match, or "" if
+// it is drawn.
+func (m model) matchWinner() string {
+	leader, alone := m.matchLeader()
+	if !alone {
+		return ""
+	}
+	return m.sideName(m.sideSeats()[leader].marker)
+}
+
+// matchScore returns the match score, highest first, e.g. "3-1".
+func (m model) matchScore() string {
+	var wins []int
+	for _, s := range m.sideSeats() {
+		wins = append(wins, s.score)
+	}
+	slices.SortFunc(wins, func(a, b int) int { return b - a })
+	var scores []string
+	for _, w := range wins {
+		scores = append(scores, fmt.Sprint(w))
+	}
+	return strings.Join(scores, "-")
+}
+
+// matchRecord returns the match, once decided, for the stats file.
+func (m model) matchRecord() matchRecord {
+	mr := matchRecord{Format: m.cfg.Match.title(), Games: m.matchGames(), Winner: m.matchWinner(), Score: m.matchScore()}
+	for i := range m.seats {
+		mr.Players = append(mr.Players, m.statsName(i))
+	}
+	return mr
+}
+
+// matchOver reports whether the match has been decided: a side has reached
+// the target of a first-to match, or a best-of match has run its length or
+// the leader is further ahead than there are games left to catch up.
+func (m model) matchOver() bool {
+	if !m.cfg.Match.enabled() {
+		return false
+	}
+	sides := m.sideSeats()
+	leader, _ := m.matchLeader()
+	if m.cfg.Match.FirstTo > 0 {
+		return sides[leader].score >= m.cfg.Match.FirstTo
+	}
+	left := m.cfg.Match.BestOf - len(m.matchGames())
+	if left <= 0 {
+		return true
+	}
+	for i, s := range sides {
+		if i != leader && sides[leader].score-s.score <= left {
+			return false
+		}
+	}
+	return true
+}
+
+// newMatch clears the scores and starts the first game of the next match.
+// The first move passes on from the last game as it would within a match.
+func (m model) newMatch() (model, tea.Cmd) {
+	m.starter = m.nextStarter()
+	m.seats = slices.Clone(m.seats)
+	for i := range m.seats {
+		m.seats[i].score = 0
+	}
+	m.games = nil
+	m.gameState = gamePlaying
+	m = m.resetGame()
+	cmd := m.clock.start(time.Now())
+	return m, tea.Batch(cmd, m.computerTurn())
+}
+
+// updateMatchOver handles the match summary screen.
+func updateMatchOver(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Place):
+			return m.newMatch()
+		case key.Matches(msg, m.keys.NewSession):
+			fresh := newModel(m.cfg)
+			fresh.width, fresh.height = m.width, m.height
+			fresh.help.Width = m.width
+			return fresh, nil
+		}
+	}
+	return m, nil
+}
+
+// matchStatus describes the match in progress for the header, e.g. "Match:
+// first to 5, game 2".
+func matchStatus(m model) string {
+	return fmt.Sprintf("Match: %s, game %d", m.cfg.Match.title(), len(m.games)+1)
+}
+
+// viewMatchOver renders the match summary: each game's result and length,
+// then the winner of the match and the final score.
+func viewMatchOver(m model) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Match Over (%s)\n\n", m.cfg.Match.title()))
+	for i, g := range m.games {
+		b.WriteString(fmt.Sprintf("Game %d: %s in %d moves\n", i+1, g.Result, g.Moves))
+	}
+
+	b.WriteRune('\n')
+	if winner := m.matchWinner(); winner != "" {
+		b.WriteString(m.theme.win.Render(fmt.Sprintf("%s wins the match %s!", winner, m.matchScore())))
+	} else {
+		b.WriteString(fmt.Sprintf("The match is drawn %s.", m.matchScore()))
+	}
+	if m.statsErr != nil {
+		b.WriteString(fmt.Sprintf("\nStats not saved: %v", m.statsErr))
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(m.help.ShortHelpView(m.keys.matchHelp()))
+	return m.place(b.String())
+}
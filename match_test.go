@@ -0,0 +1,130 @@
+// match_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// drawGame plays a drawn game, then presses enter to go on.
+func drawGame(m model) model {
+	for _, mv := range [][2]int{{0, 0}, {1, 0}, {2, 0}, {1, 1}, {0, 1}, {2, 1}, {1, 2}, {0, 2}, {2, 2}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	return placeAt(m, 0, 0)
+}
+
+// TestMatchFirstTo checks that a first-to match ends on the deciding win,
+// shows the summary, and that enter starts a new match from 0-0.
+func TestMatchFirstTo(t *testing.T) {
+	m := winForX(setupModel(config{Match: matchConfig{FirstTo: 2}}, "Ann", "Bob"))
+	if m.gameState != gamePlaying || len(m.games) != 1 {
+		t.Fatalf("Expected the match to go on after one win")
+	}
+	if !contains(m.View(), "Match: first to 2, game 2") {
+		t.Errorf("View does not show the match in progress")
+	}
+
+	for _, mv := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if !contains(m.View(), "Press enter/space for the match summary") {
+		t.Errorf("View does not point to the match summary")
+	}
+	m = placeAt(m, 0, 0)
+	if m.gameState != matchOver {
+		t.Fatalf("Expected the match to end at two wins")
+	}
+	view := m.View()
+	for _, want := range []string{"Game 1: Ann won in 5 moves", "Game 2: Ann won in 5 moves", "Ann wins the match 2-0!"} {
+		if !contains(view, want) {
+			t.Errorf("Summary does not contain %q", want)
+		}
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.gameState != gamePlaying || len(m.games) != 0 || m.seats[0].score != 0 || m.moves != 0 {
+		t.Errorf("Expected a new match with the scores cleared, got %v", m.seats)
+	}
+}
+
+// TestMatchBestOf checks that a best-of match ends once the leader cannot
+// be caught, and that draws count as games.
+func TestMatchBestOf(t *testing.T) {
+	m := winForX(winForX(setupModel(config{Match: matchConfig{BestOf: 3}}, "Ann", "Bob")))
+	if m.gameState != matchOver || len(m.games) != 2 {
+		t.Fatalf("Expected a 2-0 lead to decide a best of 3")
+	}
+
+	m = drawGame(winForX(setupModel(config{Match: matchConfig{BestOf: 3}}, "Ann", "Bob")))
+	if m.gameState != gamePlaying {
+		t.Fatalf("Expected the match to go on while Bob can still draw level")
+	}
+	m = drawGame(m)
+	if m.gameState != matchOver {
+		t.Fatalf("Expected the match to end after three games")
+	}
+	if view := m.View(); !contains(view, "Game 3: Draw in 9 moves") || !contains(view, "Ann wins the match 1-0!") {
+		t.Errorf("Summary does not report the draws and Ann's win")
+	}
+
+	m = drawGame(drawGame(setupModel(config{Match: matchConfig{BestOf: 2}}, "Ann", "Bob")))
+	if m.gameState != matchOver || !contains(m.View(), "The match is drawn 0-0.") {
+		t.Errorf("Expected a drawn match")
+	}
+}
+
+// TestMatchSaved checks that a finished match is written to the stats file
+// and read back the same.
+func TestMatchSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	m := drawGame(winForX(setupModel(config{Match: matchConfig{BestOf: 3}, statsPath: path}, "Ann", "Bob")))
+	m = drawGame(m)
+	if m.gameState != matchOver || m.statsErr != nil {
+		t.Fatalf("Expected the match to end and be saved: %v", m.statsErr)
+	}
+
+	s, err := loadStats(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := matchRecord{
+		Players: []string{"Ann", "Bob"},
+		Format:  "best of 3",
+		Games:   []gameRecord{{"Ann won", 5}, {"Draw", 9}, {"Draw", 9}},
+		Winner:  "Ann",
+		Score:   "1-0",
+	}
+	if len(s.Matches) != 1 || !reflect.DeepEqual(s.Matches[0], want) {
+		t.Errorf("Expected %+v to be saved, got %+v", want, s.Matches)
+	}
+}
+
+// TestNoMatch checks that without a match the scores run on.
+func TestNoMatch(t *testing.T) {
+	m := playingModel(config{})
+	for i := 0; i < 5; i++ {
+		m = winForX(m)
+	}
+	if m.gameState != gamePlaying || m.seats[0].score != 5 || len(m.games) != 0 {
+		t.Errorf("Expected endless games, got %v", m.seats)
+	}
+}
+
+// TestMatchConfig checks that the match length is validated.
+func TestMatchConfig(t *testing.T) {
+	for _, data := range []string{`{"match": {"first_to": 3, "best_of": 5}}`, `{"match": {"best_of": -1}}`, `{"match": {"first_to": 100}}`} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
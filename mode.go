@@ -0,0 +1,207 @@
+// mode.go
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gameMode selects the rules the game is played with.
+type gameMode int
+
+const (
+	modeClassic gameMode = iota
+	modeUltimate
+	modeGravity
+	modeQubic
+	modeNotakto
+	modeWild
+	modeOrderChaos
+	modeGomoku
+	modeMorris
+	modeNumerical
+	modeQuantum
+	modePuzzle
+)
+
+// modeNames lists the name of each mode, indexed by gameMode. These are the
+// names accepted by the "mode" setting and the --mode flag.
+var modeNames = []string{"classic", "ultimate", "gravity", "qubic", "notakto", "wild", "orderchaos", "gomoku", "morris", "numerical", "quantum", "puzzle"}
+
+// modeTitles holds the heading shown above the board for each mode.
+var modeTitles = []string{"Tic-Tac-Toe", "Ultimate Tic-Tac-Toe", "Gravity Tic-Tac-Toe", "3D Tic-Tac-Toe (Qubic)", "Notakto", "Wild Tic-Tac-Toe", "Order and Chaos", "Gomoku", "Three Men's Morris", "Numerical Tic-Tac-Toe", "Quantum Tic-Tac-Toe", "Puzzle"}
+
+// String returns the mode's name.
+func (g gameMode) String() string {
+	return modeNames[g]
+}
+
+// title returns the heading shown above the board.
+func (g gameMode) title() string {
+	return modeTitles[g]
+}
+
+// parseMode looks up a mode by name, defaulting to classic for an empty name.
+func parseMode(name string) (gameMode, error) {
+	if name == "" {
+		return modeClassic, nil
+	}
+	for i, n := range modeNames {
+		if n == name {
+			return gameMode(i), nil
+		}
+	}
+	return modeClassic, fmt.Errorf("unknown mode %q (available: %s)", name, strings.Join(modeNames, ", "))
+}
+
+// next returns the mode that follows g when cycling through modes.
+func (g gameMode) next() gameMode {
+	return gameMode((int(g) + 1) % len(modeNames))
+}
+
+// moveResult reports what an attempted move did to the game.
+type moveResult int
+
+const (
+	moveIllegal moveResult = iota // The move was not allowed; nothing changed
+	moveMade                      // The move was made and the game goes on
+	moveWon                       // The move won the game for the mover
+	moveDrawn                     // The move ended the game in a draw
+	moveLost                      // The move lost the game for the mover
+	movePartial                   // Part of the move was made; the same player goes on
+	moveFailed                    // The move failed the puzzle; the defence holds and no one scores
+)
+
+// boardSize returns the number of columns and rows the cursor moves across.
+func (m model) boardSize() (cols, rows int) {
+	switch m.mode {
+	case modeUltimate:
+		return 9, 9
+	case modeGravity, modeOrderChaos, modeGomoku, modePuzzle:
+		return m.grid.width, m.grid.height
+	case modeClassic:
+		if m.classicGrid() {
+			return m.grid.width, m.grid.height
+		}
+		return 3, 3
+	case modeQubic:
+		return qubicSize, qubicSize
+	case modeNotakto:
+		return 3 * len(m.notakto.boards), 3
+	default:
+		return 3, 3
+	}
+}
+
+// modeGrid returns the empty variable-size board the game is played on.
+func (m model) modeGrid() grid {
+	switch {
+	case m.mode == modeOrderChaos:
+		return newGrid(orderChaosSize, orderChaosSize, orderChaosLength)
+	case m.mode == modeGomoku:
+		return newGrid(gomokuSize, gomokuSize, gomokuLength)
+	case m.mode == modePuzzle:
+		return m.currentPuzzle().grid.clone()
+	case m.hasObstacles():
+		return m.blockCells(newClassicGrid(m.sides(), m.wraps(), m.obstacleGrid()))
+	case m.classicGrid():
+		return newClassicGrid(m.sides(), m.wraps(), m.cfg.Grid)
+	default:
+		return newGravityGrid(m.cfg.Grid)
+	}
+}
+
+// misereRules reports whether misère play is in effect. Notakto is a misère
+// game already, and reversing it would reward killing the last board. Order
+// and Chaos already gives each side its own objective, and in Quantum
+// Tic-Tac-Toe a collapse can complete lines for both players at once, so
+// neither has a misère form; a puzzle is always to win. With more than two
+// players there is no one opponent to score the point.
+func (m model) misereRules() bool {
+	return m.misere && m.mode != modeNotakto && m.mode != modeOrderChaos && m.mode != modeQuantum && m.mode != modePuzzle && !m.multiplayer()
+}
+
+// seatLabel returns what the player using the given marker is called in the
+// score line and turn message: their marker, their role in Order and Chaos,
+// or nothing where both players place the same markers. In a team game it
+// names the team instead, e.g. "Team X" or "Team 1".
+func (m model) seatLabel(player string) string {
+	var label string
+	switch m.mode {
+	case modeNotakto, modeWild:
+	case modeOrderChaos:
+		label = orderChaosRole(player)
+	case modeNumerical:
+		label = numericalParity(player)
+	default:
+		label = m.symbol(player)
+	}
+	if m.teams {
+		if label == "" {
+			label = fmt.Sprint(clockIndex(player) + 1)
+		}
+		return "Team " + label
+	}
+	return label
+}
+
+// seatName returns the player's name followed by their seat label, if any.
+func (m model) seatName(player string) string {
+	if label := m.seatLabel(player); label != "" {
+		return fmt.Sprintf("%s (%s)", m.playerName(player), label)
+	}
+	return m.playerName(player)
+}
+
+// placeMarker plays the current player's marker at the cursor using the
+// rules of the active mode.
+func (m *model) placeMarker() moveResult {
+	switch m.mode {
+	case modeUltimate:
+		return m.placeUltimate()
+	case modeGravity:
+		return m.placeGravity()
+	case modeQubic:
+		return m.placeQubic()
+	case modeNotakto:
+		return m.placeNotakto()
+	case modeWild:
+		return m.placeWild()
+	case modeOrderChaos:
+		return m.placeOrderChaos()
+	case modeGomoku:
+		return m.placeGomoku()
+	case modeMorris:
+		return m.placeMorris()
+	case modeNumerical:
+		return m.placeNumerical()
+	case modeQuantum:
+		return m.placeQuantum()
+	case modePuzzle:
+		return m.placePuzzle()
+	default:
+		return m.placeClassic()
+	}
+}
+
+// placeClassic plays a move on the single 3x3 board.
+func (m *model) placeClassic() moveResult {
+	if m.classicGrid() {
+		return m.placeGrid()
+	}
+	if m.disappearingRules() {
+		return m.placeDisappearing()
+	}
+	if m.board[m.cursorY][m.cursorX] != " " {
+		return moveIllegal
+	}
+	m.board[m.cursorY][m.cursorX] = m.player
+	if won, cells := checkWinner(m.board, m.player); won {
+		m.winningCells = cells
+		return moveWon
+	}
+	if checkDraw(m.board) {
+		return moveDrawn
+	}
+	return moveMade
+}
@@ -0,0 +1,114 @@
+// morris.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// morrisPieces is the number of pieces each player places before moving.
+const morrisPieces = 3
+
+// morrisMoving reports whether every piece is down, so players move pieces
+// instead of placing them.
+func (m model) morrisMoving() bool {
+	return m.moves >= 2*morrisPieces
+}
+
+// morrisAdjacent reports whether two cells are joined by a line of the
+// board: the rows, the columns and the two diagonals through the centre.
+func morrisAdjacent(x1, y1, x2, y2 int) bool {
+	dx, dy := abs(x1-x2), abs(y1-y2)
+	if dx > 1 || dy > 1 || dx+dy == 0 {
+		return false
+	}
+	if dx == 1 && dy == 1 {
+		// Diagonal steps run along the diagonals, so one end is the centre.
+		return (x1 == 1 && y1 == 1) || (x2 == 1 && y2 == 1)
+	}
+	return true
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// morrisDestination reports whether the selected piece may move to (x, y).
+func (m model) morrisDestination(x, y int) bool {
+	if m.selected < 0 || m.board[y][x] != " " {
+		return false
+	}
+	return morrisAdjacent(m.selected%3, m.selected/3, x, y)
+}
+
+// selectMorrisPiece picks up or puts down the piece at the cursor and
+// reports whether the cursor was on one of the current player's pieces.
+func (m *model) selectMorrisPiece() bool {
+	if !m.morrisMoving() || m.board[m.cursorY][m.cursorX] != m.player {
+		return false
+	}
+	cell := m.cursorY*3 + m.cursorX
+	if m.selected == cell {
+		m.selected = -1
+	} else {
+		m.selected = cell
+	}
+	return true
+}
+
+// placeMorris places a piece at the cursor while pieces remain, and
+// afterwards moves the selected piece there. With the diagonals to move
+// along, no player can be left without a move short of a line being made.
+func (m *model) placeMorris() moveResult {
+	x, y := m.cursorX, m.cursorY
+	if !m.morrisMoving() {
+		if m.board[y][x] != " " {
+			return moveIllegal
+		}
+		m.board[y][x] = m.player
+	} else {
+		if !m.morrisDestination(x, y) {
+			return moveIllegal
+		}
+		m.board[m.selected/3][m.selected%3] = " "
+		m.board[y][x] = m.player
+		m.selected = -1
+	}
+
+	if won, cells := checkWinner(m.board, m.player); won {
+		m.winningCells = cells
+		return moveWon
+	}
+	return moveMade
+}
+
+// morrisHint tells the current player what to do next.
+func morrisHint(m model) string {
+	switch {
+	case !m.morrisMoving():
+		left := morrisPieces - m.moves/2
+		return fmt.Sprintf("Place a piece (%d left)", left)
+	case m.selected < 0:
+		return "Select one of your pieces to move"
+	default:
+		return "Move it to a highlighted cell, or select another piece"
+	}
+}
+
+// morrisMark is drawn on the cells the selected piece may move to.
+func morrisMark(t theme) string {
+	return lipgloss.NewStyle().Foreground(t.cursor).Render("•")
+}
+
+// highlighted returns the theme with markers shown in reverse video, for
+// drawing the selected piece.
+func (t theme) highlighted() theme {
+	t.x = t.x.Reverse(true)
+	t.o = t.o.Reverse(true)
+	return t
+}
@@ -0,0 +1,80 @@
+// morris_test.go
+package main
+
+import "testing"
+
+// TestMorrisAdjacent checks the lines pieces move along.
+func TestMorrisAdjacent(t *testing.T) {
+	tests := []struct {
+		x1, y1, x2, y2 int
+		want           bool
+	}{
+		{0, 0, 1, 0, true},
+		{0, 0, 0, 1, true},
+		{0, 0, 1, 1, true},
+		{1, 0, 0, 1, false}, // No diagonal away from the centre
+		{0, 0, 2, 0, false},
+		{1, 1, 1, 1, false},
+	}
+	for _, tt := range tests {
+		if got := morrisAdjacent(tt.x1, tt.y1, tt.x2, tt.y2); got != tt.want {
+			t.Errorf("morrisAdjacent(%d, %d, %d, %d) = %v, want %v", tt.x1, tt.y1, tt.x2, tt.y2, got, tt.want)
+		}
+	}
+}
+
+// TestMorrisMovement checks the select-then-move interaction once all the
+// pieces are down.
+func TestMorrisMovement(t *testing.T) {
+	m := playingModel(config{Mode: "morris"})
+	// X: (0,0) (2,0) (1,2); O: (1,0) (0,1) (2,2).
+	for _, p := range []struct{ x, y int }{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 2}, {2, 2}} {
+		m = placeAt(m, p.x, p.y)
+	}
+	if !m.morrisMoving() || m.player != "X" {
+		t.Fatalf("Expected X to start moving after six placements")
+	}
+
+	// Placing on an empty cell no longer works.
+	m = placeAt(m, 1, 1)
+	if m.board[1][1] != " " || m.player != "X" {
+		t.Fatalf("Expected a placement in the movement phase to be rejected")
+	}
+
+	// Pick up the piece at (1,2); it may move to the centre or (0,2).
+	m = placeAt(m, 1, 2)
+	if m.selected != 7 || m.player != "X" {
+		t.Fatalf("Expected X's piece at (1, 2) to be selected")
+	}
+	if !m.morrisDestination(1, 1) || !m.morrisDestination(0, 2) || m.morrisDestination(2, 1) {
+		t.Errorf("Unexpected destinations for the piece at (1, 2)")
+	}
+	if !contains(m.View(), "Move it to a highlighted cell") {
+		t.Errorf("View does not prompt for the destination")
+	}
+
+	// A cell that is not adjacent is rejected and keeps the selection.
+	m = placeAt(m, 2, 1)
+	if m.board[1][2] != " " || m.selected != 7 {
+		t.Errorf("Expected a move to a non-adjacent cell to be rejected")
+	}
+
+	m = placeAt(m, 1, 1)
+	if m.board[1][1] != "X" || m.board[2][1] != " " || m.player != "O" || m.selected != -1 {
+		t.Fatalf("Expected X to move to the centre and pass the turn")
+	}
+
+	// From a fresh position, X completes the anti-diagonal by moving.
+	m = playingModel(config{Mode: "morris"})
+	m.moves = 2 * morrisPieces
+	m.board = [3][3]string{
+		{"O", "O", "X"},
+		{" ", "X", " "},
+		{" ", "X", "O"},
+	}
+	m = placeAt(m, 1, 2)
+	m = placeAt(m, 0, 2)
+	if m.winner != "X" || len(m.winningCells) != 3 {
+		t.Errorf("Expected X to win with the anti-diagonal, got winner %q", m.winner)
+	}
+}
@@ -0,0 +1,295 @@
+// notakto.go
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Limits on the number of Notakto boards.
+const (
+	minNotaktoBoards     = 1
+	maxNotaktoBoards     = 4
+	defaultNotaktoBoards = 3
+)
+
+// notaktoBoard holds a Notakto game: both players place X on any live board,
+// a board dies once it holds three in a row, and whoever kills the last live
+// board loses.
+type notaktoBoard struct {
+	boards [][3][3]string
+}
+
+// newNotaktoBoard returns n empty boards.
+func newNotaktoBoard(n int) notaktoBoard {
+	if n < minNotaktoBoards || n > maxNotaktoBoards {
+		n = defaultNotaktoBoards
+	}
+	nb := notaktoBoard{boards: make([][3][3]string, n)}
+	for b := range nb.boards {
+		nb.boards[b] = [3][3]string{{" ", " ", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	}
+	return nb
+}
+
+// validateNotaktoBoards reports an error for an unsupported number of boards.
+func validateNotaktoBoards(n int) error {
+	if n != 0 && (n < minNotaktoBoards || n > maxNotaktoBoards) {
+		return fmt.Errorf("notakto boards must be between %d and %d, got %d", minNotaktoBoards, maxNotaktoBoards, n)
+	}
+	return nil
+}
+
+// dead reports whether board b holds three in a row.
+func (nb notaktoBoard) dead(b int) bool {
+	won, _ := checkWinner(nb.boards[b], "X")
+	return won
+}
+
+// allDead reports whether every board is dead.
+func (nb notaktoBoard) allDead() bool {
+	for b := range nb.boards {
+		if !nb.dead(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// placeNotakto places an X at the cursor. Killing the last live board loses.
+func (m *model) placeNotakto() moveResult {
+	b, x, y := m.cursorX/3, m.cursorX%3, m.cursorY
+	if m.notakto.dead(b) || m.notakto.boards[b][y][x] != " " {
+		return moveIllegal
+	}
+
+	// Copy the boards so earlier copies of the model are left untouched.
+	m.notakto.boards = slices.Clone(m.notakto.boards)
+	m.notakto.boards[b][y][x] = "X"
+	if m.notakto.allDead() {
+		return moveLost
+	}
+	return moveMade
+}
+
+// viewNotaktoBoard draws the boards side by side, greying out dead ones. It
+// returns the size needed and false when they do not fit.
+func viewNotaktoBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	const gap = 2
+	n := len(m.notakto.boards)
+	// Each board gets a label line above it.
+	reservedLines++
+
+	lay := defaultLayout
+	if m.width > 0 && m.height > 0 {
+		ok = false
+		for _, c := range layoutsFor(m.cfg.Glyphs) {
+			lay = c
+			w, h := c.boardSize(3, 3)
+			needWidth, needHeight = n*(w+2)+(n-1)*gap, h+2+reservedLines
+			if needWidth <= m.width && needHeight <= m.height {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return "", needWidth, needHeight, false
+		}
+	}
+
+	var boards []string
+	for b := 0; b < n; b++ {
+		dead := m.notakto.dead(b)
+		var rows []string
+		for y := 0; y < 3; y++ {
+			var rowItems []string
+			for x := 0; x < 3; x++ {
+				isCursor := m.cursorX == b*3+x && m.cursorY == y
+				cell := renderCell(m, lay, m.notakto.boards[b][y][x], isCursor, false)
+				if dead {
+					cell = lipgloss.NewStyle().Faint(true).Render(cell)
+				}
+				rowItems = append(rowItems, cell)
+			}
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
+		}
+
+		box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder(), true).BorderForeground(m.theme.border)
+		label := fmt.Sprintf("Board %d", b+1)
+		if dead {
+			box = box.Faint(true)
+			label = lipgloss.NewStyle().Faint(true).Render(label + " (dead)")
+		} else if m.cursorX/3 == b {
+			box = box.BorderForeground(m.theme.cursor)
+		}
+		board := box.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+		boards = append(boards, lipgloss.JoinVertical(lipgloss.Center, label, board))
+		if b < n-1 {
+			boards = append(boards, lipgloss.NewStyle().Width(gap).Render(""))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, boards...), needWidth, needHeight, true
+}
+
+// A Notakto board is searched as a 9-bit mask of its X cells, bit y*3+x.
+
+// notaktoLineMasks holds the masks of the eight lines on a 3x3 board.
+var notaktoLineMasks = []uint16{
+	0b000000111, 0b000111000, 0b111000000, // Rows
+	0b001001001, 0b010010010, 0b100100100, // Columns
+	0b100010001, 0b001010100, // Diagonals
+}
+
+// notaktoSymmetries maps each cell to its image under the eight rotations
+// and reflections of the board.
+var notaktoSymmetries = func() [8][9]int {
+	var syms [8][9]int
+	for s := 0; s < 8; s++ {
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				tx, ty := x, y
+				if s&4 != 0 {
+					tx, ty = ty, tx // Reflect in the main diagonal
+				}
+				for r := 0; r < s&3; r++ {
+					tx, ty = 2-ty, tx // Rotate a quarter turn
+				}
+				syms[s][y*3+x] = ty*3 + tx
+			}
+		}
+	}
+	return syms
+}()
+
+// deadMask reports whether the mask holds three in a row.
+func deadMask(mask uint16) bool {
+	for _, line := range notaktoLineMasks {
+		if mask&line == line {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalMasks maps every mask to the smallest mask among its symmetries,
+// so equivalent boards share one entry in the search cache.
+var canonicalMasks = func() [512]uint16 {
+	var canon [512]uint16
+	for mask := range canon {
+		best := uint16(mask)
+		for _, sym := range notaktoSymmetries {
+			var t uint16
+			for i := 0; i < 9; i++ {
+				if mask&(1<<i) != 0 {
+					t |= 1 << sym[i]
+				}
+			}
+			best = min(best, t)
+		}
+		canon[mask] = best
+	}
+	return canon
+}()
+
+// notaktoWins caches whether the player to move wins a position, keyed by
+// notaktoKey.
+var notaktoWins = map[uint64]bool{}
+
+// notaktoKey packs the board count and the sorted canonical masks of the live
+// boards into one number, nine bits per board.
+func notaktoKey(live []uint16) uint64 {
+	var canon [maxNotaktoBoards]uint16
+	for i, mask := range live {
+		canon[i] = canonicalMasks[mask]
+	}
+	sorted := canon[:len(live)]
+	slices.Sort(sorted)
+	key := uint64(len(live))
+	for _, mask := range sorted {
+		key = key<<9 | uint64(mask)
+	}
+	return key
+}
+
+// notaktoPlayerToMoveWins reports whether the player to move can force a win
+// with the given live boards. With no live boards left the previous player
+// killed the last one and lost.
+func notaktoPlayerToMoveWins(live []uint16) bool {
+	if len(live) == 0 {
+		return true
+	}
+	key := notaktoKey(live)
+	if wins, ok := notaktoWins[key]; ok {
+		return wins
+	}
+
+	wins := false
+	for i := 0; i < len(live) && !wins; i++ {
+		for bit := 0; bit < 9 && !wins; bit++ {
+			if live[i]&(1<<bit) == 0 && !notaktoPlayerToMoveWins(notaktoAfter(live, i, bit)) {
+				wins = true
+			}
+		}
+	}
+	notaktoWins[key] = wins
+	return wins
+}
+
+// notaktoAfter returns the live boards after an X is placed on board i.
+func notaktoAfter(live []uint16, i, bit int) []uint16 {
+	next := make([]uint16, 0, len(live))
+	for j, mask := range live {
+		if j == i {
+			mask |= 1 << bit
+			if deadMask(mask) {
+				continue
+			}
+		}
+		next = append(next, mask)
+	}
+	return next
+}
+
+// notaktoMove picks a move for the computer: a winning move when one exists,
+// otherwise any move that does not kill the last live board.
+func notaktoMove(nb notaktoBoard) (b, x, y int, ok bool) {
+	var live []uint16
+	var index []int // Board number of each live mask
+	for i, board := range nb.boards {
+		if nb.dead(i) {
+			continue
+		}
+		var mask uint16
+		for cy := 0; cy < 3; cy++ {
+			for cx := 0; cx < 3; cx++ {
+				if board[cy][cx] == "X" {
+					mask |= 1 << (cy*3 + cx)
+				}
+			}
+		}
+		live = append(live, mask)
+		index = append(index, i)
+	}
+
+	fallback := -1
+	for i := range live {
+		for bit := 0; bit < 9; bit++ {
+			if live[i]&(1<<bit) != 0 {
+				continue
+			}
+			next := notaktoAfter(live, i, bit)
+			if !notaktoPlayerToMoveWins(next) {
+				return index[i], bit % 3, bit / 3, true
+			}
+			if fallback < 0 || len(next) > 0 {
+				fallback = i*9 + bit
+			}
+		}
+	}
+	if fallback < 0 {
+		return 0, 0, 0, false
+	}
+	return index[fallback/9], fallback % 9 % 3, fallback % 9 / 3, true
+}
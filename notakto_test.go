@@ -0,0 +1,102 @@
+// notakto_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestNotaktoSolver checks the solver against the known results for empty
+// boards: the first player wins on an odd number of boards.
+func TestNotaktoSolver(t *testing.T) {
+	for n := 1; n <= maxNotaktoBoards; n++ {
+		want := n%2 == 1
+		if got := notaktoPlayerToMoveWins(make([]uint16, n)); got != want {
+			t.Errorf("First player wins on %d empty boards = %v, want %v", n, got, want)
+		}
+	}
+}
+
+// TestNotaktoPlay checks that both players place X and that killing the last
+// live board loses.
+func TestNotaktoPlay(t *testing.T) {
+	m := playingModel(config{Mode: "notakto", Boards: 2, Computer: false})
+	m = placeAt(m, 0, 0)
+	m = placeAt(m, 3, 0)
+	if m.notakto.boards[0][0][0] != "X" || m.notakto.boards[1][0][0] != "X" {
+		t.Fatalf("Expected both players to place X")
+	}
+
+	// Kill board 2, leaving board 1 as the last live board.
+	m.notakto.boards[1] = [3][3]string{{"X", "X", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	m = placeAt(m, 5, 0)
+	if !m.notakto.dead(1) || m.winner != "" {
+		t.Fatalf("Expected board 2 to die without ending the game")
+	}
+
+	// Moves on a dead board are rejected.
+	player := m.player
+	m = placeAt(m, 4, 1)
+	if m.player != player {
+		t.Errorf("Expected a move on a dead board to be rejected")
+	}
+
+	// P2 kills the last board and loses.
+	m.notakto.boards[0] = [3][3]string{{"X", " ", " "}, {"X", " ", " "}, {" ", " ", " "}}
+	m = placeAt(m, 0, 2)
+	if m.winner != "X" || m.lineLoser != "O" || m.seats[0].score != 1 {
+		t.Errorf("Expected P2 to lose, got winner %q loser %q", m.winner, m.lineLoser)
+	}
+	if !contains(m.View(), "P2 killed the last board and loses! P1 wins!") {
+		t.Errorf("View does not report the Notakto loss")
+	}
+}
+
+// TestNotaktoMisere checks that misère play cannot turn Notakto back into a
+// game where killing the last board wins.
+func TestNotaktoMisere(t *testing.T) {
+	m := playingModel(config{Mode: "notakto", Boards: 1, Misere: true})
+	if m.misereRules() || m.keys.Misere.Enabled() {
+		t.Fatalf("Expected misère play not to apply to Notakto")
+	}
+	m.notakto.boards[0] = [3][3]string{{"X", "X", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	m = placeAt(m, 2, 0)
+	if m.winner != "O" || m.lineLoser != "X" || m.seats[1].score != 1 {
+		t.Errorf("Expected P1 to lose by killing the board, got winner %q", m.winner)
+	}
+}
+
+// TestNotaktoComputer checks that the computer answers with a winning move.
+func TestNotaktoComputer(t *testing.T) {
+	m := playingModel(config{Mode: "notakto", Boards: 1, Computer: true})
+	// With X in two corners of a row, the only safe replies avoid the
+	// middle of that row.
+	m.notakto.boards[0] = [3][3]string{{"X", " ", "X"}, {" ", " ", " "}, {" ", " ", " "}}
+	m.player, m.turn = "O", 1
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.notakto.boards[0][0][0] != "X" || m.moves != 0 || cmd != nil {
+		t.Fatalf("Expected the human not to move for the computer")
+	}
+
+	updatedModel, _ = m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.moves != 1 || m.player != "X" {
+		t.Fatalf("Expected the computer to move, got %d moves", m.moves)
+	}
+	if m.notakto.boards[0][0][1] == "X" || m.winner != "" {
+		t.Errorf("Expected the computer not to kill the board")
+	}
+}
+
+// TestNotaktoComputerTurn checks that the computer is asked to move after the human.
+func TestNotaktoComputerTurn(t *testing.T) {
+	m := playingModel(config{Mode: "notakto", Boards: 1, Computer: true})
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.player != "O" || cmd == nil {
+		t.Errorf("Expected a command for the computer's move")
+	}
+}
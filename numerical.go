@@ -0,0 +1,115 @@
+// numerical.go
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// numericalTarget is the sum a full line needs to win.
+const numericalTarget = 15
+
+// numericalParity returns the parity of the numbers the player using the
+// given marker places: player 1 has the odd numbers, player 2 the even.
+func numericalParity(player string) string {
+	if player == "X" {
+		return "odd"
+	}
+	return "even"
+}
+
+// numbersLeft returns the numbers the player has not yet placed, smallest
+// first.
+func numbersLeft(board [3][3]string, player string) []int {
+	used := map[string]bool{}
+	for _, row := range board {
+		for _, cell := range row {
+			used[cell] = true
+		}
+	}
+	var left []int
+	for n := 1; n <= 9; n++ {
+		if (n%2 == 1) == (player == "X") && !used[strconv.Itoa(n)] {
+			left = append(left, n)
+		}
+	}
+	return left
+}
+
+// numberKey returns the number picked by the digit key k, and false for any
+// other key.
+func numberKey(k string) (int, bool) {
+	if len(k) != 1 || k[0] < '1' || k[0] > '9' {
+		return 0, false
+	}
+	return int(k[0] - '0'), true
+}
+
+// pickNumber arms number n if the current player still has it.
+func (m *model) pickNumber(n int) {
+	for _, left := range numbersLeft(m.board, m.player) {
+		if left == n {
+			m.number = n
+		}
+	}
+}
+
+// checkSum returns a full line of the board whose numbers add up to
+// numericalTarget, or nil if there is none.
+func checkSum(board [3][3]string) []struct{ x, y int } {
+	for _, line := range classicLines {
+		sum, full := 0, true
+		for _, c := range line {
+			n, err := strconv.Atoi(board[c.y][c.x])
+			if err != nil {
+				full = false
+				break
+			}
+			sum += n
+		}
+		if full && sum == numericalTarget {
+			return line
+		}
+	}
+	return nil
+}
+
+// placeNumerical places the armed number at the cursor. Whoever completes a
+// line adding up to 15 wins, whichever numbers are in it.
+func (m *model) placeNumerical() moveResult {
+	if m.board[m.cursorY][m.cursorX] != " " || m.number == 0 {
+		return moveIllegal
+	}
+	m.board[m.cursorY][m.cursorX] = strconv.Itoa(m.number)
+
+	// Arm the next player's smallest number.
+	m.number = 0
+	if left := numbersLeft(m.board, opponent(m.player)); len(left) > 0 {
+		m.number = left[0]
+	}
+
+	if cells := checkSum(m.board); cells != nil {
+		m.winningCells = cells
+		return moveWon
+	}
+	if checkDraw(m.board) {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// numericalHint shows the current player's numbers, with the armed one
+// picked out.
+func numericalHint(m model) string {
+	style := m.theme.markerStyle(m.player)
+	var numbers []string
+	for _, n := range numbersLeft(m.board, m.player) {
+		s := strconv.Itoa(n)
+		if n == m.number {
+			s = style.Bold(true).Render("[" + s + "]")
+		}
+		numbers = append(numbers, s)
+	}
+	return fmt.Sprintf("Numbers left: %s (press %s to pick)", strings.Join(numbers, " "), m.keys.Number.Help().Key)
+}
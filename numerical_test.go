@@ -0,0 +1,76 @@
+// numerical_test.go
+package main
+
+import "testing"
+
+// TestNumericalPicker checks that players can only pick their own unused
+// numbers.
+func TestNumericalPicker(t *testing.T) {
+	m := playingModel(config{Mode: "numerical"})
+	if m.number != 1 {
+		t.Fatalf("Expected 1 to be armed, got %d", m.number)
+	}
+	m = pick(m, 4)
+	if m.number != 1 {
+		t.Errorf("Expected player 1 not to pick an even number")
+	}
+	m = pick(m, 7)
+	m = placeAt(m, 0, 0)
+	if m.board[0][0] != "7" || m.player != "O" || m.number != 2 {
+		t.Fatalf("Expected 7 to be placed and 2 armed for player 2, got %d", m.number)
+	}
+	if !contains(m.View(), "P2's turn (even)") {
+		t.Errorf("View does not show player 2's parity")
+	}
+
+	m = placeAt(m, 1, 0)
+	m = pick(m, 7)
+	if m.number != 1 {
+		t.Errorf("Expected the used 7 not to be picked, got %d", m.number)
+	}
+}
+
+// TestNumericalWin checks that a full line adding up to 15 wins for the
+// mover, whichever numbers are in it.
+func TestNumericalWin(t *testing.T) {
+	m := playingModel(config{Mode: "numerical"})
+	m.board = [3][3]string{
+		{"9", "2", " "},
+		{" ", " ", " "},
+		{" ", " ", " "},
+	}
+	m.player = "O"
+	m = pick(m, 4)
+	m = placeAt(m, 2, 0)
+	if m.winner != "O" || m.seats[1].score != 1 || len(m.winningCells) != 3 {
+		t.Errorf("Expected player 2 to win with 9 + 2 + 4, got winner %q", m.winner)
+	}
+
+	// A full line with another sum does not win.
+	m = playingModel(config{Mode: "numerical"})
+	m.board = [3][3]string{
+		{"1", "2", " "},
+		{" ", " ", " "},
+		{" ", " ", " "},
+	}
+	m = pick(m, 3)
+	m = placeAt(m, 2, 0)
+	if m.winner != "" {
+		t.Errorf("Expected no winner for 1 + 2 + 3")
+	}
+}
+
+// TestNumericalKeys checks that each digit still picks its own number when
+// another action takes one of them, and that only digits can be bound.
+func TestNumericalKeys(t *testing.T) {
+	m := playingModel(config{Mode: "numerical", Keys: map[string][]string{"up": {"1"}}})
+	m = pick(m, 3)
+	if m.number != 3 {
+		t.Errorf("Expected 3 to be armed, got %d", m.number)
+	}
+
+	k := defaultKeyMap()
+	if err := k.applyOverrides(map[string][]string{"number": {"1", "a"}}); err == nil {
+		t.Errorf("Expected an error for a number key that is not a digit")
+	}
+}
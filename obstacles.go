@@ -0,0 +1,194 @@
+// obstacles.go
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+)
+
+// blockedCell is held by a grid cell that cannot be played. No player uses
+// it, so a blocked cell is never empty and never part of a player's line.
+const blockedCell = "#"
+
+// blockedMark is drawn on blocked cells.
+const blockedMark = "▒"
+
+// shapeDefaults sizes a two-player classic board with obstacles or a shape:
+// 5x5 with three in a row.
+var shapeDefaults = gridConfig{Cols: 5, Rows: 5, Connect: 3}
+
+// boardShape selects the outline of the classic board.
+type boardShape int
+
+const (
+	shapeSquare  boardShape = iota // Every cell can be played
+	shapePlus                      // The corners are cut away
+	shapeDiamond                   // Only the cells near the centre remain
+)
+
+// boardShapeNames lists the name of each shape, indexed by boardShape. These
+// are the names accepted by the "shape" setting and the --shape flag.
+var boardShapeNames = []string{"square", "plus", "diamond"}
+
+// boardShapeTitles holds the name shown in the header for each shape.
+var boardShapeTitles = []string{"Square", "Plus", "Diamond"}
+
+// String returns the shape's name.
+func (s boardShape) String() string {
+	return boardShapeNames[s]
+}
+
+// title returns the name shown in the header.
+func (s boardShape) title() string {
+	return boardShapeTitles[s]
+}
+
+// parseBoardShape looks up a shape by name, defaulting to square for an
+// empty name.
+func parseBoardShape(name string) (boardShape, error) {
+	if name == "" {
+		return shapeSquare, nil
+	}
+	for i, n := range boardShapeNames {
+		if n == name {
+			return boardShape(i), nil
+		}
+	}
+	return shapeSquare, fmt.Errorf("unknown board shape %q (available: %s)", name, strings.Join(boardShapeNames, ", "))
+}
+
+// blocks reports whether the shape cuts (x, y) away from a width×height
+// board. A plus loses a third of each side at every corner; a diamond keeps
+// the cells whose distance from the centre, counted in steps along the rows
+// and columns, is at most half the longer side. Distances are doubled so
+// boards with an even side have a centre too.
+func (s boardShape) blocks(x, y, width, height int) bool {
+	switch s {
+	case shapePlus:
+		bx, by := width/3, height/3
+		return (x < bx || x >= width-bx) && (y < by || y >= height-by)
+	case shapeDiamond:
+		return abs(2*x-(width-1))+abs(2*y-(height-1)) > max(width, height)
+	default:
+		return false
+	}
+}
+
+// loadLayout reads a board layout file: one row per line, with "." for an
+// open cell and "#" for a blocked one. Rows shorter than the longest are
+// blocked at the end, so any outline can be drawn. It returns the blocked
+// cells as layout[y][x].
+func loadLayout(path string) ([][]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading layout: %w", err)
+	}
+	defer f.Close()
+
+	var rows []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rows = append(rows, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading layout: %w", err)
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+
+	width, open := 0, 0
+	for i, row := range rows {
+		width = max(width, len(row))
+		for _, c := range row {
+			switch c {
+			case '.':
+				open++
+			case '#':
+			default:
+				return nil, fmt.Errorf("layout %s line %d: unexpected %q (use . for an open cell and # for a blocked one)", path, i+1, c)
+			}
+		}
+	}
+	for _, side := range []int{width, len(rows)} {
+		if side < minGridSide || side > maxGridSide {
+			return nil, fmt.Errorf("layout %s: sides must be between %d and %d, got %dx%d", path, minGridSide, maxGridSide, width, len(rows))
+		}
+	}
+	if open == 0 {
+		return nil, fmt.Errorf("layout %s has no open cells", path)
+	}
+
+	layout := make([][]bool, len(rows))
+	for y, row := range rows {
+		layout[y] = make([]bool, width)
+		for x := range layout[y] {
+			layout[y][x] = x >= len(row) || row[x] == '#'
+		}
+	}
+	return layout, nil
+}
+
+// checkLayout reports a layout too small for the configured connect length
+// to fit on. It is run once the config file and the flags are both applied,
+// as either can set the layout or the connect length.
+func (cfg config) checkLayout() error {
+	if cfg.layout == nil {
+		return nil
+	}
+	gc := gridConfig{Cols: len(cfg.layout[0]), Rows: len(cfg.layout), Connect: cfg.Grid.Connect}
+	if err := gc.validate(); err != nil {
+		return fmt.Errorf("layout %s: %w", cfg.Layout, err)
+	}
+	return nil
+}
+
+// hasObstacles reports whether the classic board has blocked cells, from a
+// layout file, a shape, or random obstacles.
+func (m model) hasObstacles() bool {
+	return m.mode == modeClassic && (m.cfg.layout != nil || m.shape != shapeSquare || m.cfg.Obstacles > 0)
+}
+
+// obstacleGrid returns the size of the classic board with obstacles: that of
+// the layout file if there is one, and otherwise from the config.
+func (m model) obstacleGrid() gridConfig {
+	gc := m.cfg.Grid
+	if m.cfg.layout != nil {
+		gc.Cols, gc.Rows = len(m.cfg.layout[0]), len(m.cfg.layout)
+	}
+	if !m.multiplayer() {
+		gc = gc.withDefaults(shapeDefaults.sizedFor(gc))
+	}
+	return gc
+}
+
+// blockCells blocks the cells of g cut away by the layout file and the
+// shape, then the random obstacles. With a seed the obstacles are the same
+// every game; without one they are new each game. At most half the open
+// cells are blocked at random, so there is always room to play.
+func (m model) blockCells(g grid) grid {
+	var open []struct{ x, y int }
+	for y := range g.cells {
+		for x := range g.cells[y] {
+			if (m.cfg.layout != nil && m.cfg.layout[y][x]) || m.shape.blocks(x, y, g.width, g.height) {
+				g.cells[y][x] = blockedCell
+			} else {
+				open = append(open, struct{ x, y int }{x, y})
+			}
+		}
+	}
+
+	seed := m.cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	rng.Shuffle(len(open), func(i, j int) { open[i], open[j] = open[j], open[i] })
+	for _, c := range open[:min(m.cfg.Obstacles, len(open)/2)] {
+		g.cells[c.y][c.x] = blockedCell
+	}
+	return g
+}
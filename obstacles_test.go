@@ -0,0 +1,152 @@
+// obstacles_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeLayout writes a layout file and returns its path.
+func writeLayout(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "board.txt")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing layout: %v", err)
+	}
+	return path
+}
+
+// countBlocked returns the number of blocked cells on the grid.
+func countBlocked(g grid) int {
+	n := 0
+	for _, row := range g.cells {
+		for _, cell := range row {
+			if cell == blockedCell {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// TestBoardShapes checks the cells cut away by each shape.
+func TestBoardShapes(t *testing.T) {
+	testCases := map[string]struct {
+		shape string
+		want  int
+	}{
+		"Plus":    {"plus", 4},
+		"Diamond": {"diamond", 12},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m := newModel(config{Shape: tc.shape})
+			if m.grid.width != 5 || m.grid.height != 5 {
+				t.Fatalf("Expected a 5x5 board, got %dx%d", m.grid.width, m.grid.height)
+			}
+			if got := countBlocked(m.grid); got != tc.want {
+				t.Errorf("Expected %d blocked cells, got %d", tc.want, got)
+			}
+		})
+	}
+
+	// A diamond keeps the centre and the middle of each edge.
+	m := newModel(config{Shape: "diamond"})
+	for _, c := range [][2]int{{2, 2}, {2, 0}, {0, 2}, {4, 2}, {2, 4}, {1, 1}} {
+		if m.grid.cells[c[1]][c[0]] == blockedCell {
+			t.Errorf("Expected (%d, %d) to be open", c[0], c[1])
+		}
+	}
+}
+
+// TestObstaclesSeed checks that a seed gives the same obstacles every game.
+func TestObstaclesSeed(t *testing.T) {
+	cfg := config{Obstacles: 5, Seed: 42}
+	m := newModel(cfg)
+	if got := countBlocked(m.grid); got != 5 {
+		t.Fatalf("Expected 5 obstacles, got %d", got)
+	}
+	again := newModel(cfg).resetGame()
+	for y := range m.grid.cells {
+		if !slices.Equal(m.grid.cells[y], again.grid.cells[y]) {
+			t.Fatalf("Expected the same obstacles with the same seed")
+		}
+	}
+
+	// Obstacles never take more than half the board.
+	m = newModel(config{Obstacles: 100})
+	if got := countBlocked(m.grid); got != 12 {
+		t.Errorf("Expected 12 obstacles on a 5x5 board, got %d", got)
+	}
+}
+
+// TestBlockedCells checks that blocked cells can't be played, break lines,
+// and don't keep a full board from being a draw.
+func TestBlockedCells(t *testing.T) {
+	layout, err := loadLayout(writeLayout(t, "...\n.#.\n...\n"))
+	if err != nil {
+		t.Fatalf("loadLayout() error = %v", err)
+	}
+	m := playingModel(config{layout: layout})
+	if !contains(m.View(), blockedMark) {
+		t.Errorf("View does not draw the blocked cell")
+	}
+
+	m = placeAt(m, 1, 1)
+	if m.moves != 0 || m.player != "X" {
+		t.Fatalf("Expected the blocked cell not to take a move")
+	}
+
+	// X O X / O # X / O X O fills the board without a line.
+	for _, mv := range [][2]int{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {2, 1}, {0, 2}, {1, 2}, {2, 2}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if !m.isDraw || m.winner != "" {
+		t.Errorf("Expected a draw with the open cells full, got winner %q", m.winner)
+	}
+}
+
+// TestLoadLayout checks that layout files are read and checked.
+func TestLoadLayout(t *testing.T) {
+	layout, err := loadLayout(writeLayout(t, ".....\n.#.\n.....\n"))
+	if err != nil {
+		t.Fatalf("loadLayout() error = %v", err)
+	}
+	if len(layout) != 3 || len(layout[0]) != 5 || !layout[1][1] || !layout[1][4] || layout[1][2] {
+		t.Errorf("Expected short rows to be blocked at the end, got %v", layout)
+	}
+
+	for _, contents := range []string{"...\n.x.\n...\n", "..\n..\n", "###\n###\n###\n"} {
+		if _, err := loadLayout(writeLayout(t, contents)); err == nil {
+			t.Errorf("Expected an error for layout %q", contents)
+		}
+	}
+	if _, err := loadConfig(writeConfig(t, `{"shape": "hexagon"}`)); err == nil {
+		t.Errorf("Expected an error for an unknown shape")
+	}
+}
+
+// TestLayoutConnect checks that a connect length longer than the layout's
+// sides is refused, whether it comes from the config file or from the
+// --layout and --connect flags applied over it.
+func TestLayoutConnect(t *testing.T) {
+	path := writeLayout(t, "...\n...\n...\n")
+	if _, err := loadConfig(writeConfig(t, `{"layout": "`+path+`", "grid": {"connect": 5}}`)); err == nil {
+		t.Errorf("Expected an error for connect 5 on a 3x3 layout in the config")
+	}
+
+	// As main applies --layout and then --connect.
+	cfg := config{}
+	cfg.layout, _ = loadLayout(path)
+	cfg.Layout = path
+	cfg.Grid = gridConfig{Connect: 5}.withDefaults(cfg.Grid)
+	if err := cfg.checkLayout(); err == nil {
+		t.Errorf("Expected an error for --connect 5 with a 3x3 --layout")
+	}
+	cfg.Grid.Connect = 3
+	if err := cfg.checkLayout(); err != nil {
+		t.Errorf("Expected connect 3 to fit a 3x3 layout, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+// opening.go
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// openingRule selects a fairness rule for the start of a game, to make up
+// for the first player's edge on larger boards.
+type openingRule int
+
+const (
+	openingNone     openingRule = iota // X simply moves first
+	openingSwap                        // After X's first move, O may take it over
+	openingSwap2                       // Gomoku: the first player sets up three stones and the second chooses
+	openingBalanced                    // Gomoku: the game starts from a random balanced position
+)
+
+// openingRuleNames lists the name of each rule, indexed by openingRule.
+// These are the names accepted by the "opening" setting and the --opening
+// flag.
+var openingRuleNames = []string{"none", "swap", "swap2", "balanced"}
+
+// openingRuleTitles holds the name shown in the header for each rule.
+var openingRuleTitles = []string{"", "Swap rule", "Swap2", "Balanced opening"}
+
+// Stones in a Swap2 opening: three from the first player, and two more from
+// the second if they choose to place them.
+const (
+	swap2Stones    = 3
+	swap2Extension = 2
+)
+
+// balancedStones is the number of stones each side is given in a balanced
+// opening, placed within balancedRadius of the centre. Neither side may be
+// able to force a win within balancedDepth moves of its own; a deeper
+// search takes too long on the Gomoku board. After balancedAttempts layouts
+// that fail, the game starts from the empty board instead.
+const (
+	balancedStones   = 2
+	balancedRadius   = 2
+	balancedDepth    = 2
+	balancedAttempts = 100
+)
+
+// String returns the rule's name.
+func (r openingRule) String() string {
+	return openingRuleNames[r]
+}
+
+// title returns the name shown in the header.
+func (r openingRule) title() string {
+	return openingRuleTitles[r]
+}
+
+// parseOpeningRule looks up a rule by name, defaulting to none for an empty
+// name.
+func parseOpeningRule(name string) (openingRule, error) {
+	if name == "" {
+		return openingNone, nil
+	}
+	for i, n := range openingRuleNames {
+		if n == name {
+			return openingRule(i), nil
+		}
+	}
+	return openingNone, fmt.Errorf("unknown opening rule %q (available: %s)", name, strings.Join(openingRuleNames, ", "))
+}
+
+// openings returns the opening rules the mode can be played with. Swap2 and
+// balanced openings need the open Gomoku board; the swap rule suits any mode
+// in which X and O play alike.
+func (g gameMode) openings() []openingRule {
+	switch g {
+	case modeGomoku:
+		return []openingRule{openingNone, openingSwap, openingSwap2, openingBalanced}
+	case modeClassic, modeUltimate, modeGravity, modeQubic:
+		return []openingRule{openingNone, openingSwap}
+	default:
+		return nil
+	}
+}
+
+// nextOpening returns the rule that follows r among those the mode has.
+func (g gameMode) nextOpening(r openingRule) openingRule {
+	rules := g.openings()
+	if len(rules) == 0 {
+		return openingNone
+	}
+	return rules[(slices.Index(rules, r)+1)%len(rules)]
+}
+
+// openingRule returns the opening rule in effect: the chosen one, if the
+// mode has it and there are two sides to choose between.
+func (m model) openingRule() openingRule {
+	if m.sides() != minPlayers || !slices.Contains(m.mode.openings(), m.opening) {
+		return openingNone
+	}
+	return m.opening
+}
+
+// canSwap reports whether the player to move may swap sides: straight after
+// X's first move under the swap rule, and at the two choices of Swap2.
+func (m model) canSwap() bool {
+	if m.swapped || m.winner != "" || m.isDraw {
+		return false
+	}
+	switch m.openingRule() {
+	case openingSwap:
+		return m.moves == 1
+	case openingSwap2:
+		return (m.moves == swap2Stones && !m.extended) || (m.moves == swap2Stones+swap2Extension && m.extended)
+	default:
+		return false
+	}
+}
+
+// canExtend reports whether the second player may place two more stones
+// instead of choosing a side, at the first choice of Swap2.
+func (m model) canExtend() bool {
+	return m.openingRule() == openingSwap2 && m.moves == swap2Stones && !m.extended && !m.swapped && m.winner == ""
+}
+
+// swapSides exchanges the players' markers. The stones on the board stay
+// put, so whoever played X now plays O and the other player takes over the
+// X stones. O is still to move, now played by the other side.
+func (m *model) swapSides() {
+	m.seats = slices.Clone(m.seats)
+	for i := range m.seats {
+		m.seats[i].marker = opponent(m.seats[i].marker)
+	}
+	m.turn = m.seatOf(m.player)
+	m.swapped = true
+}
+
+// extend starts the second player's two extra Swap2 stones, black first.
+func (m *model) extend() {
+	m.extended = true
+	m.player = "X"
+	m.forbidden = m.forbiddenPoints()
+}
+
+// openingPlayer returns the marker of the next stone when the same player
+// places it as part of an opening, after m.moves stones are down. Under
+// Swap2 the first player places black, white and black, and the second
+// player's extra stones are black then white; with two extra stones White
+// moves next, and the second player is White until the first player
+// chooses.
+func (m model) openingPlayer() (string, bool) {
+	if m.openingRule() != openingSwap2 {
+		return "", false
+	}
+	switch {
+	case m.moves < swap2Stones:
+		return opponent(m.player), true
+	case m.extended && m.moves < swap2Stones+swap2Extension:
+		return opponent(m.player), true
+	case m.extended && m.moves == swap2Stones+swap2Extension:
+		return "O", true
+	default:
+		return "", false
+	}
+}
+
+// placeBalancedOpening puts balancedStones stones of each side at random near
+// the centre of the grid, so X moves first from an even position. Layouts
+// that are not fair are drawn again.
+func (m *model) placeBalancedOpening() {
+	cx, cy := m.grid.width/2, m.grid.height/2
+	for range balancedAttempts {
+		g := m.grid.clone()
+		for i := 0; i < 2*balancedStones; i++ {
+			for {
+				x := cx + rand.IntN(2*balancedRadius+1) - balancedRadius
+				y := cy + rand.IntN(2*balancedRadius+1) - balancedRadius
+				if g.cells[y][x] == " " {
+					g.cells[y][x] = markers[i%2]
+					break
+				}
+			}
+		}
+		if fairOpening(g) {
+			m.grid = g
+			return
+		}
+	}
+}
+
+// fairOpening reports whether neither side starts with a threat: no two
+// stones of a side in a row, and no win either side could force within
+// balancedDepth moves if it were to move.
+func fairOpening(g grid) bool {
+	if hasRun(g, 2) {
+		return false
+	}
+	if _, _, ok := winningMove(g, balancedDepth); ok {
+		return false
+	}
+	_, _, ok := winningMove(swapStones(g), balancedDepth)
+	return !ok
+}
+
+// swapStones returns a copy of g with every X stone made O and every O
+// stone made X, so the solver, which always plays X, can play for O.
+func swapStones(g grid) grid {
+	g = g.clone()
+	for y := range g.cells {
+		for x, cell := range g.cells[y] {
+			if cell == "X" || cell == "O" {
+				g.cells[y][x] = opponent(cell)
+			}
+		}
+	}
+	return g
+}
+
+// hasRun reports whether any stone on the grid is part of a run of at least
+// n stones of its own.
+func hasRun(g grid, n int) bool {
+	for y := range g.cells {
+		for x, cell := range g.cells[y] {
+			if cell == " " {
+				continue
+			}
+			for _, d := range gridDirections {
+				if len(g.run(x, y, d.dx, d.dy)) >= n {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// openingHint tells the players what the opening rule lets them do next.
+func openingHint(m model) string {
+	swap, extend := m.keys.Swap.Help().Key, m.keys.Extend.Help().Key
+	switch {
+	case m.openingRule() == openingSwap && m.canSwap():
+		return fmt.Sprintf("Press %s to swap sides and take over X's first move, or play on as O", swap)
+	case m.openingRule() != openingSwap2:
+		return ""
+	case m.moves < swap2Stones:
+		return fmt.Sprintf("Swap2: place black, white and black (stone %d of %d)", m.moves+1, swap2Stones)
+	case m.canExtend():
+		return fmt.Sprintf("Place White's stone, press %s to take Black, or press %s to place two more stones", swap, extend)
+	case m.extended && m.moves < swap2Stones+swap2Extension:
+		return "Place one more black and one more white stone"
+	case m.canSwap():
+		other := m.seats[(m.turn+1)%len(m.seats)].name
+		return fmt.Sprintf("%s may press %s to take White; otherwise White plays on", other, swap)
+	default:
+		return ""
+	}
+}
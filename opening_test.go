@@ -0,0 +1,156 @@
+// opening_test.go
+package main
+
+import "testing"
+
+// TestSwapRule checks that O can take over X's first move, and that the
+// score then follows the players rather than the markers.
+func TestSwapRule(t *testing.T) {
+	m := setupModel(config{Mode: "classic", Opening: "swap"}, "Ann", "Bob")
+	m = placeAt(m, 0, 0)
+	if !contains(m.View(), "swap sides") {
+		t.Errorf("View does not offer the swap")
+	}
+
+	m = pressKey(m, 's')
+	if m.seats[0].marker != "O" || m.seats[1].marker != "X" || m.player != "O" {
+		t.Fatalf("Expected Bob to take X and Ann to play O, got %v", m.seats)
+	}
+	view := m.View()
+	if !contains(view, "Ann's turn (O)") || !contains(view, "Score: Ann (O) 0 - 0 Bob (X)") {
+		t.Errorf("View does not follow the swapped sides")
+	}
+
+	// The swap is only offered once.
+	m = pressKey(m, 's')
+	if m.seats[0].marker != "O" {
+		t.Fatalf("Expected a second swap to be refused")
+	}
+
+	for _, mv := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {2, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if m.winner != "X" || m.seats[1].score != 1 || m.seats[0].score != 0 {
+		t.Fatalf("Expected Bob to score with X, got winner %q and %v", m.winner, m.seats)
+	}
+	if !contains(m.View(), "Bob wins!") {
+		t.Errorf("View does not report Bob's win")
+	}
+
+	// Everyone starts the next game with their own marker again.
+	m = m.resetGame()
+	if m.seats[0].marker != "X" || m.seats[1].marker != "O" || m.seats[1].score != 1 {
+		t.Errorf("Expected the markers to be reset and the score kept, got %v", m.seats)
+	}
+}
+
+// TestSwapRuleLate checks that the swap is refused once O has moved.
+func TestSwapRuleLate(t *testing.T) {
+	m := setupModel(config{Mode: "classic", Opening: "swap"}, "Ann", "Bob")
+	m = placeAt(m, 0, 0)
+	m = placeAt(m, 1, 1)
+	m = pressKey(m, 's')
+	if m.swapped || m.seats[0].marker != "X" {
+		t.Errorf("Expected no swap after O's first move")
+	}
+}
+
+// TestSwap2 checks the Swap2 opening: three stones from the first player,
+// two more from the second, then the first player's choice of side.
+func TestSwap2(t *testing.T) {
+	m := setupModel(config{Mode: "gomoku", Opening: "swap2"}, "Ann", "Bob")
+	for i, want := range []string{"X", "O", "X"} {
+		if m.player != want || m.turn != 0 {
+			t.Fatalf("Stone %d: expected Ann to place %s, got seat %d placing %s", i+1, want, m.turn, m.player)
+		}
+		m = placeAt(m, 5+i, 7)
+	}
+	if m.turn != 1 || m.player != "O" || !m.canExtend() {
+		t.Fatalf("Expected Bob to choose after three stones")
+	}
+
+	m = pressKey(m, 'e')
+	for i, want := range []string{"X", "O"} {
+		if m.player != want || m.turn != 1 {
+			t.Fatalf("Extra stone %d: expected Bob to place %s, got seat %d placing %s", i+1, want, m.turn, m.player)
+		}
+		m = placeAt(m, 5+i, 9)
+	}
+	if m.player != "O" || !m.canSwap() {
+		t.Fatalf("Expected White to move with Ann's choice pending")
+	}
+
+	m = pressKey(m, 's')
+	if m.seats[0].marker != "O" || m.turn != 0 || m.player != "O" {
+		t.Errorf("Expected Ann to take White and move, got %v", m.seats)
+	}
+}
+
+// TestSwap2TakeBlack checks that the second player can take Black after the
+// first three stones, leaving the first player to move as White.
+func TestSwap2TakeBlack(t *testing.T) {
+	m := setupModel(config{Mode: "gomoku", Opening: "swap2"}, "Ann", "Bob")
+	for i := 0; i < swap2Stones; i++ {
+		m = placeAt(m, 5+i, 7)
+	}
+	m = pressKey(m, 's')
+	if m.seats[1].marker != "X" || m.turn != 0 || m.player != "O" {
+		t.Fatalf("Expected Bob to take Black and Ann to move as White, got %v", m.seats)
+	}
+	m = placeAt(m, 5, 8)
+	if m.turn != 1 || m.player != "X" {
+		t.Errorf("Expected Bob to move next as Black")
+	}
+}
+
+// TestBalancedOpening checks that a balanced opening gives both sides the
+// same stones without a threat, with X to move.
+func TestBalancedOpening(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		m := playingModel(config{Mode: "gomoku", Opening: "balanced"}).resetGame()
+		count := map[string]int{}
+		for _, row := range m.grid.cells {
+			for _, cell := range row {
+				count[cell]++
+			}
+		}
+		if count["X"] != balancedStones || count["O"] != balancedStones {
+			t.Fatalf("Expected %d stones each, got %v", balancedStones, count)
+		}
+		if hasRun(m.grid, 2) || m.player != "X" || m.moves != 0 {
+			t.Fatalf("Expected an even position with X to move")
+		}
+	}
+
+	// A side that could force a win makes the opening unfair, and a board
+	// with no fair layout starts empty.
+	g := newGrid(5, 5, 3)
+	g.cells[1][1], g.cells[3][3] = "O", "O"
+	if fairOpening(g) {
+		t.Errorf("Expected O's split diagonal to be unfair")
+	}
+	m := playingModel(config{Mode: "gomoku", Opening: "balanced"})
+	m.grid = newGrid(5, 5, 2)
+	m.placeBalancedOpening()
+	if len(emptyCells(m.grid)) != 25 {
+		t.Errorf("Expected no stones when no layout is fair")
+	}
+
+	// The opening rule only applies where the mode has it.
+	if m := playingModel(config{Mode: "classic", Opening: "balanced"}); m.openingRule() != openingNone {
+		t.Errorf("Expected no balanced opening in classic mode")
+	}
+}
+
+// TestOpeningKeyOverride checks that rebinding the swap key to move the
+// cursor leaves the swap without a key.
+func TestOpeningKeyOverride(t *testing.T) {
+	m := playingModel(config{Mode: "gomoku", Opening: "swap", Keys: map[string][]string{"down": {"s"}}})
+	m = pressKey(m, 's')
+	if m.cursorY != 1 {
+		t.Errorf("Expected 's' to move the cursor down, got row %d", m.cursorY)
+	}
+	if m.keys.Swap.Enabled() {
+		t.Errorf("Expected the swap binding to lose its only key")
+	}
+}
@@ -0,0 +1,45 @@
+// orderchaos.go
+package main
+
+// The Order and Chaos board and the line Order needs to win.
+const (
+	orderChaosSize   = 6
+	orderChaosLength = 5
+)
+
+// orderChaosRole returns the role of the player using the given marker.
+// Player 1 plays Order and moves first; player 2 plays Chaos.
+func orderChaosRole(player string) string {
+	if player == "X" {
+		return "Order"
+	}
+	return "Chaos"
+}
+
+// placeOrderChaos places the armed marker at the cursor. Order wins as soon
+// as five of either marker stand in a row, and Chaos wins if the board fills
+// up without one, so a line made by Chaos loses and the move that fills the
+// board wins for Chaos.
+func (m *model) placeOrderChaos() moveResult {
+	if m.grid.cells[m.cursorY][m.cursorX] != " " {
+		return moveIllegal
+	}
+
+	m.grid = m.grid.clone()
+	m.grid.cells[m.cursorY][m.cursorX] = m.armed
+	isOrder := orderChaosRole(m.player) == "Order"
+	if cells := m.grid.lineThrough(m.cursorX, m.cursorY); cells != nil {
+		m.winningCells = cells
+		if isOrder {
+			return moveWon
+		}
+		return moveLost
+	}
+	if m.grid.full() {
+		if isOrder {
+			return moveLost
+		}
+		return moveWon
+	}
+	return moveMade
+}
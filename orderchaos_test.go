@@ -0,0 +1,69 @@
+// orderchaos_test.go
+package main
+
+import "testing"
+
+// TestOrderChaosOrderWins checks that five in a row wins for Order, even
+// when Chaos placed some of the markers.
+func TestOrderChaosOrderWins(t *testing.T) {
+	m := playingModel(config{Mode: "orderchaos"})
+	if m.grid.width != 6 || m.grid.height != 6 {
+		t.Fatalf("Expected a 6x6 board, got %dx%d", m.grid.width, m.grid.height)
+	}
+	if !contains(m.View(), "Score: P1 (Order) 0 - 0 P2 (Chaos)") {
+		t.Errorf("Score line does not show the roles")
+	}
+
+	// Order and Chaos take turns placing O along the top row.
+	for x := 0; x < 4; x++ {
+		m.armed = "O"
+		m = placeAt(m, x, 0)
+	}
+	m.armed = "O"
+	m = placeAt(m, 4, 0)
+	if m.winner != "X" || m.seats[0].score != 1 || len(m.winningCells) != 5 {
+		t.Errorf("Expected Order to win with five O in a row, got winner %q", m.winner)
+	}
+}
+
+// TestOrderChaosChaosLine checks that Chaos loses by completing a line.
+func TestOrderChaosChaosLine(t *testing.T) {
+	m := playingModel(config{Mode: "orderchaos"})
+	for x := 0; x < 4; x++ {
+		m.grid.cells[0][x] = "X"
+	}
+	m.player = "O"
+	m = placeAt(m, 4, 0)
+	if m.winner != "X" || m.lineLoser != "O" {
+		t.Errorf("Expected Chaos to lose, got winner %q", m.winner)
+	}
+	if !contains(m.View(), "P2 (Chaos) completed a line and loses!") {
+		t.Errorf("View does not report Chaos completing a line")
+	}
+}
+
+// TestOrderChaosChaosWins checks that filling the board without a line wins
+// for Chaos.
+func TestOrderChaosChaosWins(t *testing.T) {
+	m := playingModel(config{Mode: "orderchaos"})
+	// Pairs of columns alternate X and O, so no line of five can form.
+	pattern := []string{"X", "X", "O", "O", "X", "X"}
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			m.grid.cells[y][x] = pattern[(x+y*2)%6]
+		}
+	}
+	m.grid.cells[5][5] = " "
+	if m.grid.full() {
+		t.Fatal("Expected an empty cell")
+	}
+	m.player = "O"
+	m.armed = pattern[(5+5*2)%6]
+	m = placeAt(m, 5, 5)
+	if m.winner != "O" || m.seats[1].score != 1 {
+		t.Fatalf("Expected Chaos to win on a full board, got winner %q (cells %v)", m.winner, m.winningCells)
+	}
+	if !contains(m.View(), "The board is full with no line of 5!") {
+		t.Errorf("View does not report Chaos filling the board")
+	}
+}
@@ -0,0 +1,241 @@
+// players.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Limits on the number of players at the table.
+const (
+	minPlayers = 2
+	maxPlayers = 4
+)
+
+// markers are the markers the seats play with, in turn order. The board
+// always holds these; a player's own symbol is only used when drawing.
+var markers = []string{"X", "O", "△", "□"}
+
+// seat is one player at the table.
+type seat struct {
+	name   string
+	marker string // The marker placed on the board, from markers
+	symbol string // What the marker is drawn as, if the player chose one
+	score  int
+}
+
+// playerConfig is the config file form of a player's look.
+type playerConfig struct {
+	Symbol string `json:"symbol"` // A single character, e.g. "★"
+	Color  string `json:"color"`  // A colour lipgloss understands, e.g. "#ff8800" or "202"
+}
+
+// validatePlayers reports an error for too many players or a symbol that is
+// not a single character.
+func validatePlayers(players []playerConfig) error {
+	if len(players) > maxPlayers {
+		return fmt.Errorf("at most %d players are supported, got %d", maxPlayers, len(players))
+	}
+	seen := map[string]bool{}
+	for i, p := range players {
+		if p.Symbol == "" {
+			continue
+		}
+		if len([]rune(p.Symbol)) != 1 || strings.TrimSpace(p.Symbol) == "" {
+			return fmt.Errorf("player %d symbol must be a single character, got %q", i+1, p.Symbol)
+		}
+		if seen[p.Symbol] {
+			return fmt.Errorf("player %d symbol %q is already taken", i+1, p.Symbol)
+		}
+		seen[p.Symbol] = true
+	}
+	return nil
+}
+
+// checkModePlayers reports a mode that cannot seat the configured players.
+// It is run once the config file and the flags are both applied, as either
+// can set the mode.
+func (cfg config) checkModePlayers() error {
+	if mode, _ := parseMode(cfg.Mode); !cfg.Teams && len(cfg.Players) > mode.playerLimit() {
+		return fmt.Errorf("mode %s is for %d players, got %d", mode, mode.playerLimit(), len(cfg.Players))
+	}
+	return nil
+}
+
+// symbolChoices are the symbols the symbol key cycles through on the name
+// input screen, after the seat's own marker.
+var symbolChoices = []string{"★", "♦", "●", "♥", "♣", "♠"}
+
+// cycleSymbol gives player i the next symbol not taken by another player,
+// going back to the seat's own marker after the last one.
+func (m *model) cycleSymbol(i int) {
+	players := slices.Clone(m.cfg.Players)
+	for len(players) <= i {
+		players = append(players, playerConfig{})
+	}
+	taken := map[string]bool{}
+	for j, p := range players {
+		if j != i {
+			taken[p.Symbol] = true
+		}
+	}
+	choices := append([]string{""}, symbolChoices...)
+	next := slices.Index(choices, players[i].Symbol)
+	for {
+		next = (next + 1) % len(choices)
+		if choices[next] == "" || !taken[choices[next]] {
+			break
+		}
+	}
+	players[i].Symbol = choices[next]
+	m.cfg.Players = players
+	m.seats = newSeats(len(m.inputs), m.cfg.Players, m.teams)
+}
+
+// setupSymbol returns what row i of the name input screen will be drawn as:
+// the player's symbol, or else the seat's marker.
+func (m model) setupSymbol(i int) string {
+	if i < len(m.cfg.Players) && m.cfg.Players[i].Symbol != "" {
+		return m.cfg.Players[i].Symbol
+	}
+	return seatMarker(i, m.teams)
+}
+
+// newSeats returns n seats, taking each player's symbol from the config. In
+// a team game the seats alternate between the two sides' markers.
+func newSeats(n int, players []playerConfig, teams bool) []seat {
+	seats := make([]seat, n)
+	for i := range seats {
+		seats[i].marker = seatMarker(i, teams)
+		if i < len(players) {
+			seats[i].symbol = players[i].Symbol
+		}
+	}
+	return seats
+}
+
+// seatMarker returns the marker seat i starts each game with.
+func seatMarker(i int, teams bool) string {
+	if teams {
+		return markers[i%2]
+	}
+	return markers[i]
+}
+
+// resetMarkers hands out the markers for a new game: X to the starting
+// seat and the rest in turn order after it, whatever the sides were in the
+// last game.
+func (m *model) resetMarkers() {
+	m.seats = slices.Clone(m.seats)
+	for k := range m.seats {
+		m.seats[(m.starter+k)%len(m.seats)].marker = seatMarker(k, m.teams)
+	}
+}
+
+// playerLimit returns how many players can play the mode. Only the modes
+// whose rules work for any number of markers take more than two.
+func (g gameMode) playerLimit() int {
+	switch g {
+	case modeClassic, modeGravity:
+		return maxPlayers
+	default:
+		return minPlayers
+	}
+}
+
+// multiplayer reports whether more than two sides are at the table.
+func (m model) multiplayer() bool {
+	return m.sides() > minPlayers
+}
+
+// seatOf returns the index of the seat playing the given marker. The seat
+// whose turn it is wins a tie.
+func (m model) seatOf(marker string) int {
+	if m.turn < len(m.seats) && m.seats[m.turn].marker == marker {
+		return m.turn
+	}
+	for i, s := range m.seats {
+		if s.marker == marker {
+			return i
+		}
+	}
+	return 0
+}
+
+// nextTurn passes the turn to the next seat, unless the same player places
+// the next stone of an opening. Each turn starts with X armed, so a marker
+// switched to in Wild or Order and Chaos is not left for the next player.
+func (m *model) nextTurn() {
+	m.armed = "X"
+	if player, ok := m.openingPlayer(); ok {
+		m.player = player
+		return
+	}
+	m.turn = (m.seatOf(m.player) + 1) % len(m.seats)
+	m.player = m.seats[m.turn].marker
+}
+
+// symbol returns what the given marker is drawn as.
+func (m model) symbol(marker string) string {
+	for _, s := range m.seats {
+		if s.marker == marker && s.symbol != "" {
+			return s.symbol
+		}
+	}
+	return marker
+}
+
+// withPlayerColors returns the theme with each player's chosen colour
+// applied to the marker their seat plays. Teammates share a marker, so the
+// first of them to choose a colour sets it. The monochrome theme keeps its
+// plain markers.
+func (t theme) withPlayerColors(players []playerConfig, seats []seat) theme {
+	if t.name == "monochrome" {
+		return t
+	}
+	t.extra = slices.Clone(t.extra)
+	for i := min(len(players), len(seats)) - 1; i >= 0; i-- {
+		if players[i].Color == "" {
+			continue
+		}
+		color := lipgloss.Color(players[i].Color)
+		switch marker := seats[i].marker; marker {
+		case markers[0]:
+			t.x = t.x.Foreground(color)
+		case markers[1]:
+			t.o = t.o.Foreground(color)
+		default:
+			j := slices.Index(markers, marker) - 2
+			t.extra[j] = t.extra[j].Foreground(color)
+		}
+	}
+	return t
+}
+
+// playerTheme returns the named theme with the players' colours on the
+// markers they play this game.
+func (m model) playerTheme(name string) theme {
+	t, _ := themeByName(name)
+	return t.withPlayerColors(m.cfg.Players, m.seats)
+}
+
+// viewScores renders every player's score, e.g. "Score: Ann (X) 2 - 1 Bob (O)".
+// Teams are scored together. The first player is always on the left, even
+// when the sides were swapped.
+func viewScores(m model) string {
+	first, second := m.seats[0].marker, m.seats[1].marker
+	if m.teams {
+		return fmt.Sprintf("Score: %s %d - %d %s", m.sideName(first), m.seats[0].score, m.seats[1].score, m.sideName(second))
+	}
+	if !m.multiplayer() {
+		return fmt.Sprintf("Score: %s %d - %d %s", m.seatName(first), m.seats[0].score, m.seats[1].score, m.seatName(second))
+	}
+	var parts []string
+	for _, s := range m.seats {
+		parts = append(parts, fmt.Sprintf("%s %d", m.seatName(s.marker), s.score))
+	}
+	return "Score: " + strings.Join(parts, " • ")
+}
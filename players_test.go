@@ -0,0 +1,157 @@
+// players_test.go
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestPlayersRotation checks that the turn passes through every seat.
+func TestPlayersRotation(t *testing.T) {
+	m := setupModel(config{Players: []playerConfig{{}, {}, {Symbol: "★"}}}, "Ann", "Bob", "Cy")
+	if m.grid.width != 5 || m.grid.height != 5 || m.grid.winLength != 3 {
+		t.Fatalf("Expected a 5x5 board with three in a row, got %dx%d/%d", m.grid.width, m.grid.height, m.grid.winLength)
+	}
+
+	for i, want := range []string{"O", "△", "X"} {
+		m = placeAt(m, i, 4)
+		if m.player != want {
+			t.Fatalf("After move %d expected %s to play, got %s", i+1, want, m.player)
+		}
+	}
+	if m.grid.cells[4][2] != "△" {
+		t.Errorf("Expected the third player's marker on the board, got %q", m.grid.cells[4][2])
+	}
+	if !contains(m.View(), "★") {
+		t.Errorf("View does not draw the third player's symbol")
+	}
+}
+
+// TestPlayersWin checks that a line of the third marker credits the third
+// player.
+func TestPlayersWin(t *testing.T) {
+	m := setupModel(config{Players: []playerConfig{{}, {}, {Symbol: "★"}}}, "Ann", "Bob", "Cy")
+	moves := [][2]int{{0, 0}, {0, 1}, {0, 2}, {4, 0}, {4, 1}, {1, 2}, {4, 4}, {3, 4}, {2, 2}}
+	for _, mv := range moves {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if m.winner != "△" {
+		t.Fatalf("Expected △ to win, got %q", m.winner)
+	}
+	if m.seats[2].score != 1 || m.seats[0].score != 0 || m.seats[1].score != 0 {
+		t.Errorf("Expected only Cy to score, got %v", m.seats)
+	}
+	if !contains(m.View(), "Cy wins!") {
+		t.Errorf("View does not report Cy's win")
+	}
+}
+
+// TestPlayersSetup checks adding and removing name rows on the setup screen.
+func TestPlayersSetup(t *testing.T) {
+	m := newModel(config{})
+	press := func(k tea.KeyType) {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updatedModel.(model)
+	}
+
+	press(tea.KeyCtrlN)
+	press(tea.KeyCtrlN)
+	press(tea.KeyCtrlN)
+	if len(m.inputs) != maxPlayers {
+		t.Fatalf("Expected %d name rows, got %d", maxPlayers, len(m.inputs))
+	}
+	press(tea.KeyCtrlX)
+	if len(m.inputs) != 3 {
+		t.Fatalf("Expected 3 name rows, got %d", len(m.inputs))
+	}
+
+	// Two players always remain.
+	press(tea.KeyCtrlX)
+	press(tea.KeyCtrlX)
+	if len(m.inputs) != minPlayers {
+		t.Errorf("Expected %d name rows, got %d", minPlayers, len(m.inputs))
+	}
+
+	// Modes for two can't add players.
+	m = newModel(config{Mode: "ultimate"})
+	press(tea.KeyCtrlN)
+	if len(m.inputs) != minPlayers {
+		t.Errorf("Expected ultimate mode to stay at two players, got %d", len(m.inputs))
+	}
+}
+
+// TestPlayersSymbolKey checks that the symbol key gives the focused row the
+// next free symbol, which the game then draws, and that the name screen
+// says the clock is off with more than two players.
+func TestPlayersSymbolKey(t *testing.T) {
+	m := newModel(config{Players: []playerConfig{{}, {Symbol: symbolChoices[0]}}, timeControl: timeControl{PerGame: time.Minute}})
+	press := func(k tea.KeyType) {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updatedModel.(model)
+	}
+
+	press(tea.KeyCtrlY)
+	if got := m.setupSymbol(0); got != symbolChoices[1] {
+		t.Fatalf("Expected the taken %s to be skipped, got %s", symbolChoices[0], got)
+	}
+	if !contains(m.View(), symbolChoices[1]+" ") {
+		t.Errorf("Name screen does not show the chosen symbol")
+	}
+	if contains(m.View(), "Time control: off") {
+		t.Errorf("Expected the clock to stay on for two players")
+	}
+
+	press(tea.KeyCtrlN)
+	if !contains(m.View(), "Time control: off") {
+		t.Errorf("Name screen does not say the clock is off for three players")
+	}
+	press(tea.KeyEnter)
+	press(tea.KeyEnter)
+	press(tea.KeyEnter)
+	if m.gameState != gamePlaying || m.symbol("X") != symbolChoices[1] || m.timeControl.enabled() {
+		t.Errorf("Expected the game to draw X as %s without a clock", symbolChoices[1])
+	}
+}
+
+// TestPlayersConfig checks that bad player settings are rejected.
+func TestPlayersConfig(t *testing.T) {
+	for _, data := range []string{
+		`{"players": [{"symbol": "ab"}]}`,
+		`{"players": [{"symbol": "★"}, {"symbol": "★"}]}`,
+		`{"players": [{}, {}, {}, {}, {}]}`,
+		`{"mode": "qubic", "players": [{}, {}, {}]}`,
+	} {
+		if _, err := loadConfig(writeConfig(t, data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+
+	cfg, err := loadConfig(writeConfig(t, `{"players": [{"symbol": "★", "color": "#ffaf00"}, {}, {}]}`))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if len(newModel(cfg).inputs) != 3 {
+		t.Errorf("Expected a name row for each configured player")
+	}
+}
+
+// TestPlayersModeFlag checks that a mode given by --mode is checked against
+// the players in the config file, as main does once the flags are applied.
+func TestPlayersModeFlag(t *testing.T) {
+	cfg, err := loadConfig(writeConfig(t, `{"players": [{}, {}, {}]}`))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	for _, mode := range []string{"qubic", "notakto", "morris", "quantum", "puzzle"} {
+		cfg.Mode = mode
+		if err := cfg.checkModePlayers(); err == nil {
+			t.Errorf("Expected an error for --mode %s with three players", mode)
+		}
+	}
+	cfg.Mode = "classic"
+	if err := cfg.checkModePlayers(); err != nil {
+		t.Errorf("Expected classic mode to seat three players, got %v", err)
+	}
+}
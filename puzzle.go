@@ -0,0 +1,419 @@
+// puzzle.go
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Limits on a puzzle, so checking it on loading and defending it stay
+// quick: the longest win allowed, in moves of the solver's own, and the
+// longest side of the board. An empty 9x9 board takes the solver about a
+// tenth of a second at three moves; larger boards soon take seconds.
+const (
+	maxPuzzleMoves = 3
+	maxPuzzleSide  = 9
+)
+
+// puzzleConnect is the line length a puzzle is won with unless it gives
+// its own.
+const puzzleConnect = 3
+
+// builtinPuzzleFile holds the puzzles that come with the game.
+//
+//go:embed puzzles.txt
+var builtinPuzzleFile string
+
+// builtinPuzzles are the puzzles that come with the game, played before any
+// from the user's puzzle file.
+var builtinPuzzles = mustParsePuzzles("puzzles.txt", builtinPuzzleFile)
+
+// puzzle is a position in which X, to move, can force a win in a given
+// number of moves against any defence.
+type puzzle struct {
+	name  string
+	moves int  // Moves X has to win in
+	grid  grid // The starting position
+}
+
+// mustParsePuzzles parses the built-in puzzles, which are checked by the
+// tests, so an error is a bug.
+func mustParsePuzzles(name, data string) []puzzle {
+	puzzles, err := parsePuzzles(name, strings.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return puzzles
+}
+
+// loadPuzzles reads a puzzle file.
+func loadPuzzles(path string) ([]puzzle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading puzzles: %w", err)
+	}
+	defer f.Close()
+	return parsePuzzles(path, f)
+}
+
+// parsePuzzles reads puzzles in the puzzle file format. Each puzzle starts
+// with a "puzzle:" line naming it, followed by a "moves:" line, an optional
+// "connect:" line giving the line length needed (three by default), and the
+// board, one row per line with X and O for the players' marks, "." for an
+// empty cell and "#" for a blocked one. Comments, which are lines starting
+// with "# " or a lone "#", and blank lines are ignored. Every puzzle is
+// solved as it is read, so one that cannot be won as stated is reported.
+func parsePuzzles(name string, r io.Reader) ([]puzzle, error) {
+	var (
+		puzzles []puzzle
+		p       *puzzleSpec
+		line    int
+	)
+	finish := func() error {
+		if p == nil {
+			return nil
+		}
+		pz, err := p.build()
+		if err != nil {
+			return fmt.Errorf("puzzles %s line %d: %w", name, p.line, err)
+		}
+		puzzles = append(puzzles, pz)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		field, value, _ := strings.Cut(text, ":")
+		value = strings.TrimSpace(value)
+		switch {
+		case text == "" || text == "#" || strings.HasPrefix(text, "# "):
+		case field == "puzzle":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			p = &puzzleSpec{name: value, line: line, connect: puzzleConnect}
+		case p == nil:
+			return nil, fmt.Errorf("puzzles %s line %d: expected a \"puzzle:\" line", name, line)
+		case field == "moves" || field == "connect":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("puzzles %s line %d: invalid %s %q", name, line, field, value)
+			}
+			if field == "moves" {
+				p.moves = n
+			} else {
+				p.connect = n
+			}
+		default:
+			if strings.Trim(text, "XO.#") != "" {
+				return nil, fmt.Errorf("puzzles %s line %d: unexpected %q (use X, O, . and #)", name, line, text)
+			}
+			p.rows = append(p.rows, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading puzzles: %w", err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if len(puzzles) == 0 {
+		return nil, fmt.Errorf("puzzles %s has no puzzles", name)
+	}
+	return puzzles, nil
+}
+
+// puzzleSpec is a puzzle as read from a puzzle file, before it is checked.
+type puzzleSpec struct {
+	name    string
+	line    int // Line of the "puzzle:" line, for errors
+	moves   int
+	connect int
+	rows    []string
+}
+
+// build checks the puzzle and returns it ready to play.
+func (p puzzleSpec) build() (puzzle, error) {
+	if p.name == "" {
+		return puzzle{}, errors.New("puzzle has no name")
+	}
+	if p.moves < 1 || p.moves > maxPuzzleMoves {
+		return puzzle{}, fmt.Errorf("puzzle %q: moves must be between 1 and %d, got %d", p.name, maxPuzzleMoves, p.moves)
+	}
+	if len(p.rows) == 0 {
+		return puzzle{}, fmt.Errorf("puzzle %q has no board", p.name)
+	}
+	width := len(p.rows[0])
+	for _, side := range []int{width, len(p.rows)} {
+		if side < minGridSide || side > maxPuzzleSide {
+			return puzzle{}, fmt.Errorf("puzzle %q: sides must be between %d and %d, got %dx%d", p.name, minGridSide, maxPuzzleSide, width, len(p.rows))
+		}
+	}
+	if p.connect < minGridSide || p.connect > max(width, len(p.rows)) {
+		return puzzle{}, fmt.Errorf("puzzle %q: connect must be between %d and the longer side, got %d", p.name, minGridSide, p.connect)
+	}
+
+	g := newGrid(width, len(p.rows), p.connect)
+	for y, row := range p.rows {
+		if len(row) != width {
+			return puzzle{}, fmt.Errorf("puzzle %q: every row must be %d cells long", p.name, width)
+		}
+		for x, c := range row {
+			switch c {
+			case 'X', 'O':
+				g.cells[y][x] = string(c)
+			case '#':
+				g.cells[y][x] = blockedCell
+			}
+		}
+	}
+	for y := range g.cells {
+		for x := range g.cells[y] {
+			if g.lineThrough(x, y) != nil {
+				return puzzle{}, fmt.Errorf("puzzle %q is already won", p.name)
+			}
+		}
+	}
+	if _, _, ok := winningMove(g, p.moves); !ok {
+		return puzzle{}, fmt.Errorf("puzzle %q: X cannot force a win in %d", p.name, p.moves)
+	}
+	return puzzle{name: p.name, moves: p.moves, grid: g}, nil
+}
+
+// emptyCells returns the cells of g that can be played.
+func emptyCells(g grid) []struct{ x, y int } {
+	var cells []struct{ x, y int }
+	for y := range g.cells {
+		for x, cell := range g.cells[y] {
+			if cell == " " {
+				cells = append(cells, struct{ x, y int }{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+// winningMove returns a move with which X, to move on g, wins within n moves
+// of its own however O defends, if there is one.
+func winningMove(g grid, n int) (x, y int, ok bool) {
+	return winIn(g.clone(), n)
+}
+
+// winIn is winningMove on a grid it may play on. Every move it tries is
+// taken back before it returns.
+func winIn(g grid, n int) (x, y int, ok bool) {
+	if n == 0 {
+		return 0, 0, false
+	}
+	cells := emptyCells(g)
+	// A move that wins at once is the best there is, so look for one first.
+	for _, c := range cells {
+		g.cells[c.y][c.x] = "X"
+		won := g.lineThrough(c.x, c.y) != nil
+		g.cells[c.y][c.x] = " "
+		if won {
+			return c.x, c.y, true
+		}
+	}
+	if n == 1 {
+		return 0, 0, false
+	}
+	for _, c := range cells {
+		g.cells[c.y][c.x] = "X"
+		ok := defenceFails(g, n-1)
+		g.cells[c.y][c.x] = " "
+		if ok {
+			return c.x, c.y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// defenceFails reports whether X wins within n more moves of its own
+// whatever O, to move on g, plays. A full board is a draw, so the defence
+// holds.
+func defenceFails(g grid, n int) bool {
+	cells := emptyCells(g)
+	if len(cells) == 0 {
+		return false
+	}
+	for _, c := range cells {
+		g.cells[c.y][c.x] = "O"
+		holds := g.lineThrough(c.x, c.y) != nil
+		if !holds {
+			_, _, won := winIn(g, n)
+			holds = !won
+		}
+		g.cells[c.y][c.x] = " "
+		if holds {
+			return false
+		}
+	}
+	return true
+}
+
+// defence returns O's reply on g when X has n moves left: one that holds if
+// there is one, and otherwise the one that makes X take longest to win.
+func defence(g grid, n int) (x, y int, ok bool) {
+	g = g.clone()
+	best := -1
+	for _, c := range emptyCells(g) {
+		g.cells[c.y][c.x] = "O"
+		wins := 1 // The number of moves X needs after this reply
+		if g.lineThrough(c.x, c.y) != nil {
+			wins = n + 1
+		}
+		for wins <= n {
+			if _, _, won := winIn(g, wins); won {
+				break
+			}
+			wins++
+		}
+		g.cells[c.y][c.x] = " "
+		if wins > best {
+			best, x, y, ok = wins, c.x, c.y, true
+		}
+	}
+	return x, y, ok
+}
+
+// currentPuzzle returns the puzzle being played.
+func (m model) currentPuzzle() puzzle {
+	return m.puzzles[m.puzzle]
+}
+
+// puzzleMovesLeft returns the number of moves X has left to win in, counting
+// the one it is about to make.
+func (m model) puzzleMovesLeft() int {
+	return m.currentPuzzle().moves - (m.moves+1)/2
+}
+
+// puzzleSolved reports whether the puzzle being played has just been solved.
+func (m model) puzzleSolved() bool {
+	return m.mode == modePuzzle && m.winner == "X" && m.flagged == "" && m.lineLoser == ""
+}
+
+// placePuzzle plays a move on the puzzle board. A move of X's that does not
+// win must keep the forced win within the moves left, and one that does not
+// fails the puzzle.
+func (m *model) placePuzzle() moveResult {
+	result := m.placeGrid()
+	if m.player != "X" || result == moveIllegal {
+		return result
+	}
+	if result == moveWon {
+		m.recordPuzzle(true)
+		return result
+	}
+	if result == moveDrawn || !defenceFails(m.grid.clone(), m.puzzleMovesLeft()-1) {
+		return moveFailed
+	}
+	return result
+}
+
+// failPuzzle ends the attempt as a failure. The defence is left the winner
+// so the game is over, but the computer scores no point for it.
+func (m *model) failPuzzle() {
+	m.recordPuzzle(false)
+	m.winner = "O"
+	m.clock.stop()
+}
+
+// abandonPuzzle counts a puzzle left unfinished as a failed attempt once a
+// move or a hint has begun it, so restarting or leaving the puzzle cannot
+// wipe out the attempt.
+func (m *model) abandonPuzzle() {
+	if m.mode == modePuzzle && m.winner == "" && (m.moves > 0 || m.hints > 0) {
+		m.recordPuzzle(false)
+	}
+}
+
+// takeHint moves the cursor to a winning move and counts the hint.
+func (m *model) takeHint() {
+	if x, y, ok := winningMove(m.grid, m.puzzleMovesLeft()); ok {
+		m.cursorX, m.cursorY = x, y
+		m.hints++
+	}
+}
+
+// nextPuzzle returns the index of the puzzle after the current one, going
+// back to the first after the last.
+func (m model) nextPuzzle() int {
+	return (m.puzzle + 1) % len(m.puzzles)
+}
+
+// puzzleProgress is how a puzzle has gone so far, kept across sessions.
+type puzzleProgress struct {
+	Solved int `json:"solved"`
+	Failed int `json:"failed"`
+	Hints  int `json:"hints"`
+}
+
+// recordPuzzle counts the end of an attempt at the current puzzle, with
+// the hints taken, and saves it with the stats.
+func (m *model) recordPuzzle(solved bool) {
+	name := m.currentPuzzle().name
+	progress := maps.Clone(m.cfg.stats.Puzzles) // Leave earlier copies of the model theirs
+	if progress == nil {
+		progress = map[string]puzzleProgress{}
+	}
+	pp := progress[name]
+	if solved {
+		pp.Solved++
+	} else {
+		pp.Failed++
+	}
+	pp.Hints += m.hints
+	progress[name] = pp
+	m.cfg.stats.Puzzles = progress
+	m.statsErr = saveStats(m.cfg.statsPath, m.cfg.stats)
+}
+
+// puzzleStatus describes the puzzle being played, with how it has gone
+// before, e.g. "Puzzle 2 of 9: The fork (solved 1, failed 2)".
+func puzzleStatus(m model) string {
+	p := m.currentPuzzle()
+	status := fmt.Sprintf("Puzzle %d of %d: %s", m.puzzle+1, len(m.puzzles), p.name)
+	if pp, ok := m.cfg.stats.Puzzles[p.name]; ok {
+		status += fmt.Sprintf(" (solved %d, failed %d)", pp.Solved, pp.Failed)
+	}
+	return status
+}
+
+// puzzleHint tells the player how many moves they have left and how to
+// get a hint.
+func puzzleHint(m model) string {
+	left := m.puzzleMovesLeft()
+	moves := "moves"
+	if left == 1 {
+		moves = "move"
+	}
+	return fmt.Sprintf("Win in %d %s against any defence. Press %s for a hint (%d used)", left, moves, m.keys.Hint.Help().Key, m.hints)
+}
+
+// puzzleResult reports how an attempt at the puzzle ended.
+func puzzleResult(m model) string {
+	place := m.keys.Place.Help().Key
+	var status string
+	switch {
+	case m.puzzleSolved():
+		status = fmt.Sprintf("Solved with %d hints! (Press %s for the next puzzle)", m.hints, place)
+	case m.flagged != "":
+		status = fmt.Sprintf("Out of time! Puzzle failed. (Press %s to try again)", place)
+	default:
+		status = fmt.Sprintf("That move lets the defence hold. Puzzle failed! (Press %s to try again)", place)
+	}
+	if m.statsErr != nil {
+		status += fmt.Sprintf("\nProgress not saved: %v", m.statsErr)
+	}
+	return status
+}
@@ -0,0 +1,229 @@
+// puzzle_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// puzzleModel returns Ann playing the given built-in puzzle.
+func puzzleModel(index int) model {
+	m := setupModel(config{Mode: "puzzle"}, "Ann")
+	m.puzzle = index
+	return m.resetGame()
+}
+
+// defend lets the computer play its reply.
+func defend(t *testing.T, m model) model {
+	t.Helper()
+	updatedModel, _ := m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.player != "X" {
+		t.Fatalf("Expected the computer to defend")
+	}
+	return m
+}
+
+// TestBuiltinPuzzles checks that every built-in puzzle has its own name
+// and can be won as stated.
+func TestBuiltinPuzzles(t *testing.T) {
+	names := map[string]bool{}
+	for _, p := range builtinPuzzles {
+		if names[p.name] {
+			t.Errorf("Puzzle %q appears twice", p.name)
+		}
+		names[p.name] = true
+		if _, _, ok := winningMove(p.grid, p.moves); !ok {
+			t.Errorf("Puzzle %q cannot be won in %d", p.name, p.moves)
+		}
+	}
+}
+
+// TestParsePuzzlesErrors checks that bad puzzle files are rejected.
+func TestParsePuzzlesErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"no header":       "XX.\nOO.\n...\n",
+		"bad cell":        "puzzle: A\nmoves: 1\nXX.\nOZ.\n...\n",
+		"bad moves":       "puzzle: A\nmoves: two\nXX.\nOO.\n...\n",
+		"too many moves":  "puzzle: A\nmoves: 4\nXX.\nOO.\n...\n",
+		"ragged rows":     "puzzle: A\nmoves: 1\nXX.\nOO\n...\n",
+		"already won":     "puzzle: A\nmoves: 1\nXXX\nOO.\n...\n",
+		"cannot be won":   "puzzle: A\nmoves: 1\nX..\n.O.\n...\n",
+		"no board":        "puzzle: A\nmoves: 1\n",
+		"empty":           "# Nothing here\n",
+		"connect too big": "puzzle: A\nmoves: 1\nconnect: 4\nXX.\nOO.\n...\n",
+		"board too big":   "puzzle: A\nmoves: 1\nXX" + strings.Repeat(".", 8) + "\nOO" + strings.Repeat(".", 8) + "\n" + strings.Repeat(strings.Repeat(".", 10)+"\n", 8),
+	} {
+		if _, err := parsePuzzles("test", strings.NewReader(data)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+
+	puzzles, err := parsePuzzles("test", strings.NewReader("# Two puzzles\n#\npuzzle: A\nmoves: 1\nXX.\nOO.\n...\n\npuzzle: B\nmoves: 2\nconnect: 3\n#.O.\n.X..\n....\n....\n"))
+	if err != nil || len(puzzles) != 2 || puzzles[1].grid.cells[0][0] != blockedCell {
+		t.Errorf("Expected two puzzles, got %d: %v", len(puzzles), err)
+	}
+}
+
+// TestPuzzleSolve checks that forcing the win solves the puzzle, counts the
+// progress, and moves on to the next puzzle.
+func TestPuzzleSolve(t *testing.T) {
+	m := puzzleModel(1) // Block and fork
+	if !contains(m.View(), "Puzzle 2 of") || !contains(m.View(), "Win in 2 moves") {
+		t.Errorf("View does not describe the puzzle")
+	}
+
+	m = placeAt(m, 2, 2) // Block the diagonal with a fork
+	if m.winner != "" || m.player != "O" {
+		t.Fatalf("Expected the block to keep the puzzle going")
+	}
+	m = defend(t, m)
+	x, y, ok := winningMove(m.grid, m.puzzleMovesLeft())
+	if !ok {
+		t.Fatalf("Expected a win after the fork")
+	}
+	m = placeAt(m, x, y)
+	if !m.puzzleSolved() || m.cfg.stats.Puzzles["Block and fork"].Solved != 1 {
+		t.Fatalf("Expected the puzzle to be solved, got %v", m.cfg.stats.Puzzles)
+	}
+	if !contains(m.View(), "Solved with 0 hints!") {
+		t.Errorf("View does not report the solve")
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.puzzle != 2 || m.moves != 0 || m.player != "X" {
+		t.Errorf("Expected the next puzzle, got puzzle %d", m.puzzle)
+	}
+}
+
+// TestPuzzleFail checks that a move losing the forced win fails the puzzle
+// without a point for the computer, and that enter tries it again.
+func TestPuzzleFail(t *testing.T) {
+	m := puzzleModel(1)
+	m = placeAt(m, 1, 0) // Leaves O's diagonal open
+	if m.puzzleSolved() || m.winner != "O" || m.cfg.stats.Puzzles["Block and fork"].Failed != 1 {
+		t.Fatalf("Expected the puzzle to fail")
+	}
+	if m.lineLoser != "" || m.seats[1].score != 0 {
+		t.Errorf("Expected the failure to score no point for the computer")
+	}
+	if !contains(m.View(), "Puzzle failed!") {
+		t.Errorf("View does not report the failure")
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.puzzle != 1 || m.moves != 0 || m.grid.cells[1][2] != "X" || m.grid.cells[0][1] != " " {
+		t.Errorf("Expected the same puzzle again from the start")
+	}
+}
+
+// TestPuzzleHintAndSkip checks that a hint points at a winning move and is
+// counted, and that the next puzzle key skips ahead, failing an attempt
+// already begun.
+func TestPuzzleHintAndSkip(t *testing.T) {
+	m := pressKey(puzzleModel(0), 'i')
+	if m.hints != 1 || m.cursorX != 2 || m.cursorY != 0 {
+		t.Fatalf("Expected the hint to point at the open end of the line, got (%d, %d)", m.cursorX, m.cursorY)
+	}
+	m = placeAt(m, m.cursorX, m.cursorY)
+	if !m.puzzleSolved() || m.cfg.stats.Puzzles["Finish the line"].Hints != 1 {
+		t.Errorf("Expected the hint to be recorded, got %v", m.cfg.stats.Puzzles)
+	}
+
+	m = pressKey(puzzleModel(len(builtinPuzzles)-1), 'n')
+	if m.puzzle != 0 {
+		t.Errorf("Expected the last puzzle to skip back to the first, got %d", m.puzzle)
+	}
+	if len(m.cfg.stats.Puzzles) != 0 {
+		t.Errorf("Expected skipping an untouched puzzle not to count, got %v", m.cfg.stats.Puzzles)
+	}
+
+	// Skipping after a hint fails the attempt and keeps the hint.
+	m = pressKey(pressKey(m, 'i'), 'n')
+	if pp := m.cfg.stats.Puzzles["Finish the line"]; m.puzzle != 1 || pp.Failed != 1 || pp.Hints != 1 {
+		t.Errorf("Expected the skipped attempt to be recorded, got %+v", pp)
+	}
+}
+
+// TestPuzzleRestart checks that restarting or leaving a puzzle after a hint
+// fails the attempt, however it is done.
+func TestPuzzleRestart(t *testing.T) {
+	keys := map[string]tea.KeyMsg{
+		"reset":       {Type: tea.KeyRunes, Runes: []rune{'r'}},
+		"mode":        {Type: tea.KeyRunes, Runes: []rune{'m'}},
+		"new session": {Type: tea.KeyCtrlR},
+	}
+	for name, msg := range keys {
+		m := pressKey(puzzleModel(0), 'i')
+		updatedModel, _ := m.Update(msg)
+		m = updatedModel.(model)
+		if pp := m.cfg.stats.Puzzles["Finish the line"]; pp.Failed != 1 || pp.Hints != 1 {
+			t.Errorf("%s: expected the attempt to be recorded, got %+v", name, pp)
+		}
+		if m.hints != 0 {
+			t.Errorf("%s: expected the hints to start again, got %d", name, m.hints)
+		}
+	}
+}
+
+// TestPuzzleDefence checks that the computer blocks an immediate threat.
+func TestPuzzleDefence(t *testing.T) {
+	m := puzzleModel(3) // Corner against edge
+	m = placeAt(m, 1, 1)
+	m = defend(t, m)
+	if m.grid.cells[2][2] != "O" {
+		t.Errorf("Expected the computer to block the diagonal")
+	}
+}
+
+// TestPuzzleProgressSaved checks that progress is written to and read back
+// from the stats file.
+func TestPuzzleProgressSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tictactoe", "stats.json")
+	m := puzzleModel(0)
+	m.cfg.statsPath = path
+	m = placeAt(m, 2, 0)
+	if m.statsErr != nil {
+		t.Fatalf("Saving progress: %v", m.statsErr)
+	}
+
+	s, err := loadStats(path)
+	if err != nil || s.Puzzles["Finish the line"].Solved != 1 {
+		t.Errorf("Expected the solve to be saved, got %v (%v)", s.Puzzles, err)
+	}
+}
+
+// TestPuzzleConfig checks that the user's puzzles are loaded from the
+// config and played after the built-in ones.
+func TestPuzzleConfig(t *testing.T) {
+	dir := t.TempDir()
+	puzzles := filepath.Join(dir, "puzzles.txt")
+	if err := os.WriteFile(puzzles, []byte("puzzle: Mine\nmoves: 1\n.XX\n.OO\n...\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"mode": "puzzle", "puzzles": "`+puzzles+`"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(cfg)
+	if len(m.puzzles) != len(builtinPuzzles)+1 || m.puzzles[len(builtinPuzzles)].name != "Mine" {
+		t.Errorf("Expected the user's puzzle after the built-in ones")
+	}
+
+	if err := os.WriteFile(puzzles, []byte("puzzle: Mine\nmoves: 1\n...\n.OO\n...\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		t.Errorf("Expected an error for a puzzle that cannot be won")
+	}
+}
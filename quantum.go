@@ -0,0 +1,302 @@
+// quantum.go
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quantumMark is a mark made on a given move: X or O and the move number,
+// shown as a subscript.
+type quantumMark struct {
+	player string
+	move   int
+}
+
+// String returns the mark with its subscripted move number, e.g. "X₃".
+func (q quantumMark) String() string {
+	return q.player + string(rune('₀'+q.move))
+}
+
+// spookyMark is a mark that is in two cells at once until it collapses. The
+// spooky marks are the edges of a graph whose nodes are the cells, so a
+// cycle of entanglement is a cycle in that graph.
+type spookyMark struct {
+	quantumMark
+	a, b int // The two cells, y*3+x
+}
+
+// quantumBoard holds a game of Quantum Tic-Tac-Toe.
+type quantumBoard struct {
+	classical   [9]quantumMark // Collapsed marks; player is "" while a cell is open
+	spooky      []spookyMark   // Marks not yet collapsed, in the order made
+	pending     int            // The first cell of the move being made, or -1
+	collapse    int            // Index in spooky of the mark that closed a cycle, or -1
+	winningMove int            // The highest subscript in the winning line
+}
+
+// newQuantumBoard returns an empty board.
+func newQuantumBoard() quantumBoard {
+	return quantumBoard{pending: -1, collapse: -1}
+}
+
+// connected reports whether cells a and b are joined through spooky marks,
+// found by a breadth-first search of the entanglement graph.
+func (q quantumBoard) connected(a, b int) bool {
+	seen := map[int]bool{a: true}
+	queue := []int{a}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == b {
+			return true
+		}
+		for _, s := range q.spooky {
+			next := -1
+			if s.a == c {
+				next = s.b
+			} else if s.b == c {
+				next = s.a
+			}
+			if next >= 0 && !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// openCells returns the cells without a classical mark.
+func (q quantumBoard) openCells() []int {
+	var open []int
+	for c, mark := range q.classical {
+		if mark.player == "" {
+			open = append(open, c)
+		}
+	}
+	return open
+}
+
+// collapseInto makes the spooky mark at index i classical in cell c. Every
+// other spooky mark in c is pushed to its other cell, and so on through the
+// entangled marks until none are left in the cycle.
+func (q *quantumBoard) collapseInto(i, c int) {
+	type step struct {
+		mark spookyMark
+		cell int
+	}
+	queue := []step{{q.spooky[i], c}}
+	q.spooky = slices.Delete(slices.Clone(q.spooky), i, i+1)
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		q.classical[s.cell] = s.mark.quantumMark
+		for j := 0; j < len(q.spooky); j++ {
+			other := q.spooky[j]
+			if other.a != s.cell && other.b != s.cell {
+				continue
+			}
+			to := other.a
+			if to == s.cell {
+				to = other.b
+			}
+			queue = append(queue, step{other, to})
+			q.spooky = slices.Delete(q.spooky, j, j+1)
+			j--
+		}
+	}
+}
+
+// quantumLine returns the player's classical line with the lowest highest
+// subscript, and that subscript, or nil if they have no line.
+func (q quantumBoard) quantumLine(player string) (line []struct{ x, y int }, last int) {
+	for _, l := range classicLines {
+		top, full := 0, true
+		for _, c := range l {
+			mark := q.classical[c.y*3+c.x]
+			if mark.player != player {
+				full = false
+				break
+			}
+			top = max(top, mark.move)
+		}
+		if full && (line == nil || top < last) {
+			line, last = l, top
+		}
+	}
+	return line, last
+}
+
+// quantumWinner decides the game from the classical marks. When both
+// players have a line, the one completed first wins: the line whose highest
+// subscript is lower.
+func (q quantumBoard) quantumWinner() (winner string, line []struct{ x, y int }, last int) {
+	xLine, xLast := q.quantumLine("X")
+	oLine, oLast := q.quantumLine("O")
+	switch {
+	case xLine != nil && (oLine == nil || xLast < oLast):
+		return "X", xLine, xLast
+	case oLine != nil:
+		return "O", oLine, oLast
+	default:
+		return "", nil, 0
+	}
+}
+
+// placeQuantum plays at the cursor. A move takes two presses, one for each
+// cell of the spooky mark; pressing the first cell again takes it back. If
+// the move closes a cycle, the other player's next press picks which of its
+// two cells the mark collapses into, and the classical marks are then
+// checked for a winner. When only one cell is open the mark is placed there
+// classically.
+func (m *model) placeQuantum() moveResult {
+	q := &m.quantum
+	cell := m.cursorY*3 + m.cursorX
+	if q.classical[cell].player != "" {
+		return moveIllegal
+	}
+	mark := quantumMark{m.player, m.moves + 1}
+
+	switch open := q.openCells(); {
+	case q.collapse >= 0:
+		s := q.spooky[q.collapse]
+		if cell != s.a && cell != s.b {
+			return moveIllegal
+		}
+		q.collapseInto(q.collapse, cell)
+		q.collapse = -1
+	case len(open) == 1:
+		q.classical[cell] = mark
+	case q.pending < 0:
+		q.pending = cell
+		return movePartial
+	case q.pending == cell:
+		q.pending = -1
+		return movePartial
+	default:
+		s := spookyMark{mark, q.pending, cell}
+		cycle := q.connected(s.a, s.b)
+		q.spooky = append(slices.Clone(q.spooky), s)
+		q.pending = -1
+		if cycle {
+			q.collapse = len(q.spooky) - 1
+		}
+		return moveMade
+	}
+
+	winner, line, last := q.quantumWinner()
+	if winner != "" {
+		m.winningCells = line
+		q.winningMove = last
+		if winner == m.player {
+			return moveWon
+		}
+		return moveLost
+	}
+	if len(q.openCells()) == 0 {
+		return moveDrawn
+	}
+	return movePartial // The collapse is done; the same player now moves
+}
+
+// quantumHint tells the current player what to do next.
+func quantumHint(m model) string {
+	q := m.quantum
+	mark := quantumMark{m.player, m.moves + 1}
+	switch {
+	case q.collapse >= 0:
+		return fmt.Sprintf("Entanglement cycle! Choose the cell %s collapses into", q.spooky[q.collapse].quantumMark)
+	case len(q.openCells()) == 1:
+		return fmt.Sprintf("Place %s in the last open cell", mark)
+	case q.pending >= 0:
+		return fmt.Sprintf("Choose a second cell for %s", mark)
+	default:
+		return fmt.Sprintf("Choose two cells for %s", mark)
+	}
+}
+
+// quantumCellWidths lists the inner cell widths tried when drawing the
+// board, largest first. Each cell holds up to three spooky marks per line.
+var quantumCellWidths = []int{11, 8}
+
+// viewQuantumBoard draws the board with every spooky mark in its cells. It
+// returns the size needed and false when it does not fit.
+func viewQuantumBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	cellWidth := quantumCellWidths[0]
+	if m.width > 0 && m.height > 0 {
+		ok = false
+		for _, w := range quantumCellWidths {
+			cellWidth = w
+			needWidth, needHeight = 3*(w+2), 3*5+reservedLines
+			if needWidth <= m.width && needHeight <= m.height {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return "", needWidth, needHeight, false
+		}
+	}
+
+	var rows []string
+	for y := 0; y < 3; y++ {
+		var rowItems []string
+		for x := 0; x < 3; x++ {
+			rowItems = append(rowItems, renderQuantumCell(m, y*3+x, cellWidth))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...), needWidth, needHeight, true
+}
+
+// renderQuantumCell draws one cell: its classical mark, or its spooky marks
+// three to a line. The first cell of a move and the cells a mark may
+// collapse into are outlined.
+func renderQuantumCell(m model, cell, width int) string {
+	q := m.quantum
+	style := lipgloss.NewStyle().
+		Width(width).
+		Height(3).
+		Align(lipgloss.Center, lipgloss.Center).
+		Border(lipgloss.NormalBorder(), true).
+		BorderForeground(m.theme.border)
+
+	chosen := cell == q.pending
+	if q.collapse >= 0 && m.winner == "" {
+		chosen = cell == q.spooky[q.collapse].a || cell == q.spooky[q.collapse].b
+	}
+	if chosen {
+		style = style.Border(lipgloss.DoubleBorder(), true).BorderForeground(m.theme.cursor)
+	}
+	if m.cursorY*3+m.cursorX == cell {
+		style = style.Border(m.theme.cursorBorder, true).BorderForeground(m.theme.cursor)
+	}
+	if m.isWinningCell(cell%3, cell/3) {
+		style = style.Inherit(m.theme.win)
+	}
+
+	if mark := q.classical[cell]; mark.player != "" {
+		return style.Inherit(m.theme.markerStyle(mark.player)).Bold(true).Render(mark.String())
+	}
+
+	var lines []string
+	var line []string
+	for _, s := range q.spooky {
+		if s.a != cell && s.b != cell {
+			continue
+		}
+		line = append(line, m.theme.markerStyle(s.player).Render(strings.ToLower(s.player)+string(rune('₀'+s.move))))
+		if len(line) == 3 {
+			lines = append(lines, strings.Join(line, " "))
+			line = nil
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, " "))
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,100 @@
+// quantum_test.go
+package main
+
+import "testing"
+
+// TestQuantumSpookyMove checks that a move takes two cells and that the
+// first can be taken back.
+func TestQuantumSpookyMove(t *testing.T) {
+	m := playingModel(config{Mode: "quantum"})
+	m = placeAt(m, 0, 0)
+	if m.quantum.pending != 0 || m.player != "X" {
+		t.Fatalf("Expected the first cell to be pending")
+	}
+	m = placeAt(m, 0, 0)
+	if m.quantum.pending != -1 || m.player != "X" {
+		t.Fatalf("Expected pressing the first cell again to take it back")
+	}
+
+	m = spooky(m, 0, 4)
+	if len(m.quantum.spooky) != 1 || m.player != "O" || m.moves != 1 {
+		t.Fatalf("Expected X's spooky mark to be made and the turn passed")
+	}
+	if s := m.quantum.spooky[0]; s.a != 0 || s.b != 4 || s.String() != "X₁" {
+		t.Errorf("Unexpected spooky mark %v", s)
+	}
+	if !contains(m.View(), "x₁") {
+		t.Errorf("View does not show the spooky mark")
+	}
+}
+
+// TestQuantumCollapse checks that a cycle lets the other player choose the
+// collapse, which settles every mark in the cycle.
+func TestQuantumCollapse(t *testing.T) {
+	m := playingModel(config{Mode: "quantum"})
+	m = spooky(m, 0, 1) // X₁
+	m = spooky(m, 1, 2) // O₂
+	m = spooky(m, 0, 2) // X₃ closes the cycle 0-1-2
+	if m.quantum.collapse != 2 || m.player != "O" {
+		t.Fatalf("Expected O to choose the collapse of X₃")
+	}
+	if !contains(m.View(), "Choose the cell X₃ collapses into") {
+		t.Errorf("View does not prompt for the collapse")
+	}
+
+	// A cell outside the cycle's last move is not a choice.
+	m = placeAt(m, 1, 0)
+	if m.quantum.collapse != 2 {
+		t.Fatalf("Expected the collapse to wait for cell 0 or 2")
+	}
+
+	m = placeAt(m, 0, 0)
+	want := []string{"X₃", "X₁", "O₂"}
+	for c, w := range want {
+		if got := m.quantum.classical[c].String(); got != w {
+			t.Errorf("Cell %d: expected %s, got %s", c, w, got)
+		}
+	}
+	if len(m.quantum.spooky) != 0 || m.player != "O" || m.winner != "" {
+		t.Errorf("Expected O to go on to make their own move")
+	}
+}
+
+// TestQuantumSimultaneousLines checks that when a collapse gives both
+// players a line, the line with the lower highest subscript wins.
+func TestQuantumSimultaneousLines(t *testing.T) {
+	q := newQuantumBoard()
+	for c, mark := range []quantumMark{
+		{"X", 1}, {"X", 3}, {"X", 7},
+		{"O", 2}, {"O", 4}, {"O", 6},
+	} {
+		q.classical[c] = mark
+	}
+	winner, line, last := q.quantumWinner()
+	if winner != "O" || last != 6 || len(line) != 3 || line[0].y != 1 {
+		t.Errorf("Expected O to win with a line up to move 6, got %q up to %d", winner, last)
+	}
+}
+
+// TestQuantumCollapseWin checks that a collapse that completes a line ends
+// the game.
+func TestQuantumCollapseWin(t *testing.T) {
+	m := playingModel(config{Mode: "quantum"})
+	m.quantum.classical[0] = quantumMark{"X", 1}
+	m.quantum.classical[1] = quantumMark{"X", 3}
+	m.quantum.spooky = []spookyMark{
+		{quantumMark{"X", 5}, 2, 5},
+		{quantumMark{"O", 6}, 5, 2},
+	}
+	m.quantum.collapse = 1
+	m.moves = 6
+
+	// Sending O₆ to cell 5 pushes X₅ into cell 2, completing the top row.
+	m = placeAt(m, 2, 1)
+	if m.winner != "X" || m.seats[0].score != 1 || len(m.winningCells) != 3 {
+		t.Fatalf("Expected X to win, got winner %q", m.winner)
+	}
+	if !contains(m.View(), "P1 completed a line first, on move 5! P1 wins!") {
+		t.Errorf("View does not report the quantum win")
+	}
+}
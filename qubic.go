@@ -0,0 +1,276 @@
+// qubic.go
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// qubicSize is the length of each side of the 4x4x4 cube.
+const qubicSize = 4
+
+// point3 is a cell in the cube: column x, row y, layer z.
+type point3 struct{ x, y, z int }
+
+// qubicBoard holds a 3D game on four stacked 4x4 layers.
+type qubicBoard struct {
+	cells   [qubicSize][qubicSize][qubicSize]string // cells[z][y][x]
+	winning []point3                                // The winning line, once there is one
+}
+
+// qubicLines holds every winning line in the cube: rows, columns and
+// diagonals within each layer, pillars and diagonals through the layers, and
+// the four space diagonals. There are 76 in all.
+var qubicLines = generateQubicLines()
+
+// generateQubicLines walks every direction from every cell and keeps the
+// lines that stay inside the cube. Each direction is only taken one way, so
+// no line is found twice.
+func generateQubicLines() [][]point3 {
+	var lines [][]point3
+	for dz := -1; dz <= 1; dz++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if !canonicalDirection(dx, dy, dz) {
+					continue
+				}
+				for z := 0; z < qubicSize; z++ {
+					for y := 0; y < qubicSize; y++ {
+						for x := 0; x < qubicSize; x++ {
+							if line := qubicLine(point3{x, y, z}, dx, dy, dz); line != nil {
+								lines = append(lines, line)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// canonicalDirection reports whether (dx, dy, dz) is non-zero and its first
+// non-zero component is positive.
+func canonicalDirection(dx, dy, dz int) bool {
+	for _, d := range []int{dx, dy, dz} {
+		if d != 0 {
+			return d > 0
+		}
+	}
+	return false
+}
+
+// qubicLine returns the full line starting at p in the given direction, or
+// nil if it leaves the cube or p is not the start of the line.
+func qubicLine(p point3, dx, dy, dz int) []point3 {
+	inside := func(q point3) bool {
+		return q.x >= 0 && q.x < qubicSize && q.y >= 0 && q.y < qubicSize && q.z >= 0 && q.z < qubicSize
+	}
+	if inside(point3{p.x - dx, p.y - dy, p.z - dz}) {
+		return nil // Not the start of the line
+	}
+	line := make([]point3, 0, qubicSize)
+	for i := 0; i < qubicSize; i++ {
+		q := point3{p.x + i*dx, p.y + i*dy, p.z + i*dz}
+		if !inside(q) {
+			return nil
+		}
+		line = append(line, q)
+	}
+	return line
+}
+
+// newQubicBoard returns an empty cube.
+func newQubicBoard() qubicBoard {
+	var q qubicBoard
+	for z := range q.cells {
+		for y := range q.cells[z] {
+			for x := range q.cells[z][y] {
+				q.cells[z][y][x] = " "
+			}
+		}
+	}
+	return q
+}
+
+// checkQubicWinner returns the line through p completed by player, if any.
+func checkQubicWinner(q qubicBoard, p point3, player string) []point3 {
+	for _, line := range qubicLines {
+		through, complete := false, true
+		for _, c := range line {
+			if c == p {
+				through = true
+			}
+			if q.cells[c.z][c.y][c.x] != player {
+				complete = false
+				break
+			}
+		}
+		if through && complete {
+			return line
+		}
+	}
+	return nil
+}
+
+// full reports whether every cell of the cube is taken.
+func (q qubicBoard) full() bool {
+	for z := range q.cells {
+		for y := range q.cells[z] {
+			for x := range q.cells[z][y] {
+				if q.cells[z][y][x] == " " {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// qubicMove chooses the computer's move for player: a cell completing one
+// of its lines if there is one, else one blocking the opponent's three in a
+// line, else the cell scoring highest over the lines through it. A line
+// still open to only one side scores more the more markers that side has
+// on it, the computer's own a little more than the opponent's.
+//
+// In misère play the aims are reversed: the computer completes its own line
+// only when every cell would, leaves the opponent the cells that would
+// complete theirs, and keeps off lines it has already started.
+func qubicMove(q qubicBoard, player string, misere bool) (point3, bool) {
+	const block = 1 << 20 // Beyond any score from the open lines
+	var best point3
+	bestScore, found := 0, false
+	for z := range q.cells {
+		for y := range q.cells[z] {
+			for x := range q.cells[z][y] {
+				if q.cells[z][y][x] != " " {
+					continue
+				}
+				p := point3{x, y, z}
+				score := 0
+				for _, line := range qubicLines {
+					if !slices.Contains(line, p) {
+						continue
+					}
+					own, other := qubicCount(q, line, player)
+					switch {
+					case own == qubicSize-1 && !misere:
+						return p, true
+					case own == qubicSize-1:
+						score -= block
+					case other == qubicSize-1 && !misere:
+						score += block
+					case other == qubicSize-1:
+						score -= block / 2
+					case other == 0 && misere:
+						score -= 1 << (2 * own)
+					case other == 0:
+						score += 1 << (2 * own)
+					case own == 0 && !misere:
+						score += 1 << (2*other - 1)
+					}
+				}
+				if !found || score > bestScore {
+					best, bestScore, found = p, score, true
+				}
+			}
+		}
+	}
+	return best, found
+}
+
+// qubicCount returns how many of the cells of line hold player's marker
+// and how many hold the opponent's.
+func qubicCount(q qubicBoard, line []point3, player string) (own, other int) {
+	for _, c := range line {
+		switch q.cells[c.z][c.y][c.x] {
+		case " ":
+		case player:
+			own++
+		default:
+			other++
+		}
+	}
+	return own, other
+}
+
+// placeQubic plays a move at the cursor on the current layer.
+func (m *model) placeQubic() moveResult {
+	p := point3{m.cursorX, m.cursorY, m.cursorZ}
+	if m.qubic.cells[p.z][p.y][p.x] != " " {
+		return moveIllegal
+	}
+	m.qubic.cells[p.z][p.y][p.x] = m.player
+	if line := checkQubicWinner(m.qubic, p, m.player); line != nil {
+		m.qubic.winning = line
+		return moveWon
+	}
+	if m.qubic.full() {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// viewQubicBoard draws the four layers side by side, outlining the layer the
+// cursor is on. It returns the size needed and false when they do not fit.
+func viewQubicBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	const gap = 2
+	// Each layer gets a label line above it.
+	reservedLines++
+
+	lay := defaultLayout
+	if m.width > 0 && m.height > 0 {
+		ok = false
+		for _, c := range layoutsFor(m.cfg.Glyphs) {
+			lay = c
+			w, h := c.boardSize(qubicSize, qubicSize)
+			needWidth, needHeight = qubicSize*(w+2)+(qubicSize-1)*gap, h+2+reservedLines
+			if needWidth <= m.width && needHeight <= m.height {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return "", needWidth, needHeight, false
+		}
+	}
+
+	var layers []string
+	for z := 0; z < qubicSize; z++ {
+		var rows []string
+		for y := 0; y < qubicSize; y++ {
+			var rowItems []string
+			for x := 0; x < qubicSize; x++ {
+				rowItems = append(rowItems, renderQubicCell(m, lay, point3{x, y, z}))
+			}
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
+		}
+
+		box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder(), true).BorderForeground(m.theme.border)
+		label := fmt.Sprintf("Layer %d", z+1)
+		if z == m.cursorZ {
+			box = box.BorderForeground(m.theme.cursor)
+			label = lipgloss.NewStyle().Foreground(m.theme.cursor).Bold(true).Render(label)
+		}
+		layer := box.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+		layers = append(layers, lipgloss.JoinVertical(lipgloss.Center, label, layer))
+		if z < qubicSize-1 {
+			layers = append(layers, lipgloss.NewStyle().Width(gap).Render(""))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, layers...), needWidth, needHeight, true
+}
+
+// renderQubicCell draws one cell of the cube, marking the winning line.
+func renderQubicCell(m model, lay cellLayout, p point3) string {
+	isCursor := p.z == m.cursorZ && p.y == m.cursorY && p.x == m.cursorX
+	isWinning := false
+	for _, w := range m.qubic.winning {
+		if w == p {
+			isWinning = true
+		}
+	}
+	return renderCell(m, lay, m.qubic.cells[p.z][p.y][p.x], isCursor, isWinning)
+}
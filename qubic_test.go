@@ -0,0 +1,128 @@
+// qubic_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestQubicLines checks the number and kinds of winning lines in the cube.
+func TestQubicLines(t *testing.T) {
+	if len(qubicLines) != 76 {
+		t.Fatalf("Expected 76 winning lines, got %d", len(qubicLines))
+	}
+
+	// Lines through a corner: row, column and pillar, three face
+	// diagonals, and one space diagonal.
+	corner := 0
+	for _, line := range qubicLines {
+		for _, p := range line {
+			if p == (point3{0, 0, 0}) {
+				corner++
+			}
+		}
+	}
+	if corner != 7 {
+		t.Errorf("Expected 7 lines through a corner, got %d", corner)
+	}
+}
+
+// TestQubicWin checks a win along a space diagonal across all four layers.
+func TestQubicWin(t *testing.T) {
+	m := playingModel(config{Mode: "qubic"})
+	for i := 0; i < 3; i++ {
+		m.qubic.cells[i][i][i] = "X"
+	}
+
+	// Move to the last layer with the layer keys and play its far corner.
+	for i := 0; i < 5; i++ {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+		m = updatedModel.(model)
+	}
+	if m.cursorZ != 3 {
+		t.Fatalf("Expected cursorZ to stop at 3, got %d", m.cursorZ)
+	}
+	m = placeAt(m, 3, 3)
+
+	if m.winner != "X" {
+		t.Fatalf("Expected X to win along the space diagonal, got %q", m.winner)
+	}
+	if len(m.qubic.winning) != 4 {
+		t.Errorf("Expected 4 winning cells, got %d", len(m.qubic.winning))
+	}
+}
+
+// TestQubicLayerKeysDisabled checks that the layer keys do nothing in flat modes.
+func TestQubicLayerKeysDisabled(t *testing.T) {
+	m := playingModel(config{})
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	m = updatedModel.(model)
+	if m.cursorZ != 0 {
+		t.Errorf("Expected cursorZ to stay 0 in classic mode, got %d", m.cursorZ)
+	}
+}
+
+// TestQubicComputer checks that the computer blocks three in a line, and
+// takes a win over a block.
+func TestQubicComputer(t *testing.T) {
+	m := playingModel(config{Mode: "qubic", Computer: true})
+	for z := 0; z < 3; z++ {
+		m.qubic.cells[z][1][2] = "X"
+	}
+	m.qubic.cells[0][0][0] = "O"
+	m.player, m.turn = "O", 1
+
+	updatedModel, _ := m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.qubic.cells[3][1][2] != "O" || m.player != "X" {
+		t.Fatalf("Expected the computer to block the pillar")
+	}
+
+	for x := 1; x < 3; x++ {
+		m.qubic.cells[0][0][x] = "O"
+	}
+	for x := 0; x < 3; x++ {
+		m.qubic.cells[2][3][x] = "X"
+	}
+	m.player, m.turn = "O", 1
+	updatedModel, _ = m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.qubic.cells[0][0][3] != "O" || m.winner != "O" {
+		t.Errorf("Expected the computer to complete its row")
+	}
+}
+
+// TestQubicComputerMisere checks that in misère play the computer keeps off
+// the cell completing its own line until it has no other move.
+func TestQubicComputerMisere(t *testing.T) {
+	m := playingModel(config{Mode: "qubic", Computer: true, Misere: true})
+	for x := 0; x < 3; x++ {
+		m.qubic.cells[0][0][x] = "O"
+	}
+	m.player, m.turn = "O", 1
+
+	updatedModel, _ := m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.moves != 1 || m.qubic.cells[0][0][3] != " " {
+		t.Fatalf("Expected the computer not to complete its own row")
+	}
+
+	// Fill the cube so that only the cell completing the row is left.
+	for z := range m.qubic.cells {
+		for y := range m.qubic.cells[z] {
+			for x := range m.qubic.cells[z][y] {
+				if m.qubic.cells[z][y][x] == " " {
+					m.qubic.cells[z][y][x] = "X"
+				}
+			}
+		}
+	}
+	m.qubic.cells[0][0][3] = " "
+	m.player, m.turn = "O", 1
+	updatedModel, _ = m.Update(computerMoveMsg{})
+	m = updatedModel.(model)
+	if m.qubic.cells[0][0][3] != "O" || m.winner != "X" {
+		t.Errorf("Expected the forced move to lose the game for the computer")
+	}
+}
@@ -0,0 +1,130 @@
+// stats.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// playerStats is how a player's games have gone, kept across sessions.
+type playerStats struct {
+	Won   int `json:"won"`
+	Lost  int `json:"lost"`
+	Drawn int `json:"drawn"`
+}
+
+// stats is everything kept across sessions in the stats file.
+type stats struct {
+	Players map[string]playerStats `json:"players,omitempty"` // By player name
+	Matches []matchRecord          `json:"matches,omitempty"` // In the order they were played
+	// Puzzles is the progress on each puzzle, by puzzle name.
+	Puzzles map[string]puzzleProgress `json:"puzzles,omitempty"`
+}
+
+// defaultStatsPath returns where stats are saved, next to the config file,
+// e.g. ~/.config/tictactoe/stats.json.
+func defaultStatsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tictactoe", "stats.json"), nil
+}
+
+// loadStats reads the saved stats. A missing file means nothing has been
+// played yet.
+func loadStats(path string) (stats, error) {
+	var s stats
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("reading stats: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return stats{}, fmt.Errorf("parsing stats %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// saveStats writes the stats to path. With no path, stats are kept for the
+// session only.
+func saveStats(path string, s stats) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("saving stats: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("saving stats: %w", err)
+	}
+	return nil
+}
+
+// statsName returns the name seat i's results are kept under: the name
+// entered, or the placeholder shown on the name input screen.
+func (m model) statsName(i int) string {
+	if name := m.seats[i].name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("Player %d", i+1)
+}
+
+// recordStats counts the finished game for every player at the table,
+// adds the match if the game decided one, and saves the stats. Each member
+// of a team is credited with the team's result. Puzzles keep their own
+// progress instead.
+func (m *model) recordStats() {
+	if m.mode == modePuzzle {
+		return
+	}
+	players := maps.Clone(m.cfg.stats.Players) // Leave earlier copies of the model theirs
+	if players == nil {
+		players = map[string]playerStats{}
+	}
+	for i, s := range m.seats {
+		ps := players[m.statsName(i)]
+		switch {
+		case m.winner == "":
+			ps.Drawn++
+		case s.marker == m.winner:
+			ps.Won++
+		default:
+			ps.Lost++
+		}
+		players[m.statsName(i)] = ps
+	}
+	m.cfg.stats.Players = players
+	if m.matchOver() {
+		m.cfg.stats.Matches = append(slices.Clip(m.cfg.stats.Matches), m.matchRecord())
+	}
+	m.statsErr = saveStats(m.cfg.statsPath, m.cfg.stats)
+}
+
+// viewRecord shows each player's results over every session, e.g.
+// "Record: Ann 3-1-0 • Bob 1-3-0", counting wins, losses and draws.
+func viewRecord(m model) string {
+	var parts []string
+	for i := range m.seats {
+		ps := m.cfg.stats.Players[m.statsName(i)]
+		parts = append(parts, fmt.Sprintf("%s %d-%d-%d", m.statsName(i), ps.Won, ps.Lost, ps.Drawn))
+	}
+	record := "Record: " + strings.Join(parts, " • ")
+	if m.statsErr != nil {
+		record += fmt.Sprintf("\nStats not saved: %v", m.statsErr)
+	}
+	return record
+}
@@ -0,0 +1,51 @@
+// stats_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestStatsTeams checks that a team's win is credited to both members and
+// its loss to both opponents, and that the record is shown after the game.
+func TestStatsTeams(t *testing.T) {
+	m := setupModel(config{Teams: true}, "Ann", "Bob", "Cy", "Dee")
+	for _, mv := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	for name, want := range map[string]playerStats{"Ann": {Won: 1}, "Cy": {Won: 1}, "Bob": {Lost: 1}, "Dee": {Lost: 1}} {
+		if got := m.cfg.stats.Players[name]; got != want {
+			t.Errorf("Expected %s to have %+v, got %+v", name, want, got)
+		}
+	}
+	if !contains(m.View(), "Record: Ann 1-0-0 • Bob 0-1-0 • Cy 1-0-0 • Dee 0-1-0") {
+		t.Errorf("View does not show the players' records")
+	}
+}
+
+// TestStatsSaved checks that stats, draws included, are written to and read
+// back from the stats file, and that a damaged file is reported.
+func TestStatsSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tictactoe", "stats.json")
+	m := playingModel(config{statsPath: path})
+	m = drawGame(m)
+	if m.statsErr != nil {
+		t.Fatalf("Saving stats: %v", m.statsErr)
+	}
+
+	s, err := loadStats(path)
+	if err != nil || s.Players["P1"].Drawn != 1 || s.Players["P2"].Drawn != 1 {
+		t.Errorf("Expected the draw to be saved, got %v (%v)", s, err)
+	}
+	if s, err := loadStats(filepath.Join(t.TempDir(), "missing.json")); err != nil || s.Players != nil {
+		t.Errorf("Expected no stats from a missing file")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadStats(path); err == nil {
+		t.Errorf("Expected an error for a damaged stats file")
+	}
+}
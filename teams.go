@@ -0,0 +1,63 @@
+// teams.go
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// teamSize is the number of players on each side in a team game. Teammates
+// share a marker and take their side's turns in rotation.
+const teamSize = 2
+
+// sides returns the number of sides in the game: two in a team game, and
+// otherwise one for each player.
+func (m model) sides() int {
+	if m.teams {
+		return 2
+	}
+	return len(m.seats)
+}
+
+// teamNames returns the names of the players using the given marker, e.g.
+// "Ann & Cy".
+func (m model) teamNames(marker string) string {
+	var names []string
+	for _, s := range m.seats {
+		if s.marker == marker {
+			names = append(names, s.name)
+		}
+	}
+	return strings.Join(names, " & ")
+}
+
+// sideName returns what the side using the given marker is called when it
+// wins or scores: the player's name, or in a team game the team and its
+// players, e.g. "Team X (Ann & Cy)".
+func (m model) sideName(marker string) string {
+	if !m.teams {
+		return m.playerName(marker)
+	}
+	return fmt.Sprintf("%s (%s)", m.seatLabel(marker), m.teamNames(marker))
+}
+
+// toggleTeams switches the setup screen between team play, with a name row
+// for each of the four players, and two players.
+func (m *model) toggleTeams() {
+	m.teams = !m.teams
+	m.cfg.Teams = m.teams
+	n := minPlayers
+	if m.teams {
+		n = 2 * teamSize
+	}
+	for len(m.inputs) < n {
+		m.inputs = append(m.inputs, m.newInput(len(m.inputs)))
+	}
+	m.inputs = m.inputs[:n]
+	if m.focusIndex >= n {
+		m.focusIndex = n - 1
+		m.inputs[m.focusIndex].Focus()
+		m.inputs[m.focusIndex].PromptStyle = m.theme.promptStyle()
+	}
+	m.seats = newSeats(n, m.cfg.Players, m.teams)
+}
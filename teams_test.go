@@ -0,0 +1,78 @@
+// teams_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestTeamsRotation checks that teammates alternate taking their side's
+// turns.
+func TestTeamsRotation(t *testing.T) {
+	m := setupModel(config{Teams: true}, "Ann", "Bob", "Cy", "Dee")
+	if !contains(m.View(), "Ann's turn (Team X)") {
+		t.Fatalf("Expected Ann to start for Team X")
+	}
+
+	for i, want := range []string{"Bob's turn (Team O)", "Cy's turn (Team X)", "Dee's turn (Team O)", "Ann's turn (Team X)"} {
+		m = placeAt(m, i%3, i/3)
+		if !contains(m.View(), want) {
+			t.Errorf("After move %d expected %q", i+1, want)
+		}
+	}
+	if m.board[0][2] != "X" {
+		t.Errorf("Expected Cy to place X, got %q", m.board[0][2])
+	}
+}
+
+// TestTeamsWin checks that a win credits both members of the team.
+func TestTeamsWin(t *testing.T) {
+	m := setupModel(config{Teams: true}, "Ann", "Bob", "Cy", "Dee")
+	// Ann, Bob, Cy, Dee, then Ann completes the top row.
+	for _, mv := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}} {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if m.winner != "X" {
+		t.Fatalf("Expected Team X to win, got %q", m.winner)
+	}
+	for i, want := range []int{1, 0, 1, 0} {
+		if m.seats[i].score != want {
+			t.Errorf("Expected %s to have %d, got %d", m.seats[i].name, want, m.seats[i].score)
+		}
+	}
+	view := m.View()
+	if !contains(view, "Team X (Ann & Cy) wins!") {
+		t.Errorf("View does not report Team X's win")
+	}
+	if !contains(view, "Score: Team X (Ann & Cy) 1 - 0 Team O (Bob & Dee)") {
+		t.Errorf("View does not show the team scores")
+	}
+}
+
+// TestTeamsSetup checks that the team key switches the setup screen between
+// two players and two teams of two.
+func TestTeamsSetup(t *testing.T) {
+	m := newModel(config{})
+	press := func(k tea.KeyType) {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updatedModel.(model)
+	}
+
+	press(tea.KeyCtrlT)
+	if !m.teams || len(m.inputs) != 2*teamSize {
+		t.Fatalf("Expected four name rows for team play, got %d", len(m.inputs))
+	}
+	if !contains(m.View(), "Team O") {
+		t.Errorf("Setup screen does not show the teams")
+	}
+	press(tea.KeyCtrlN)
+	if len(m.inputs) != 2*teamSize {
+		t.Errorf("Expected players not to be added in team play")
+	}
+
+	press(tea.KeyCtrlT)
+	if m.teams || len(m.inputs) != minPlayers {
+		t.Errorf("Expected two name rows without team play, got %d", len(m.inputs))
+	}
+}
@@ -0,0 +1,162 @@
+// theme.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme holds the colours and styles used to render the game.
+type theme struct {
+	name         string
+	border       lipgloss.TerminalColor // Border of every cell
+	cursor       lipgloss.TerminalColor // Border of the cell under the cursor and the focused prompt
+	cursorBorder lipgloss.Border        // Border shape of the cell under the cursor
+	x            lipgloss.Style         // Style of the X marker
+	o            lipgloss.Style         // Style of the O marker
+	win          lipgloss.Style         // Applied on top of the markers in the winning line
+	extra        []lipgloss.Style       // Styles of the third and fourth players' markers
+}
+
+// themeNames lists the available themes in the order they are cycled through.
+var themeNames = []string{"default", "dark", "light", "solarized", "high-contrast", "colorblind", "monochrome"}
+
+// themes maps each theme name to its definition.
+var themes = map[string]theme{
+	"default": {
+		border:       lipgloss.Color("63"),
+		cursor:       lipgloss.Color("205"),
+		cursorBorder: lipgloss.NormalBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("202")),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+		win:          lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("76")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("178")),
+		},
+	},
+	"dark": {
+		border:       lipgloss.Color("240"),
+		cursor:       lipgloss.Color("212"),
+		cursorBorder: lipgloss.NormalBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
+		win:          lipgloss.NewStyle().Foreground(lipgloss.Color("203")),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("120")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("183")),
+		},
+	},
+	"light": {
+		border:       lipgloss.Color("245"),
+		cursor:       lipgloss.Color("125"),
+		cursorBorder: lipgloss.NormalBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("166")),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("25")),
+		win:          lipgloss.NewStyle().Foreground(lipgloss.Color("160")),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("28")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("90")),
+		},
+	},
+	"solarized": {
+		border:       lipgloss.Color("#586e75"),
+		cursor:       lipgloss.Color("#d33682"),
+		cursorBorder: lipgloss.NormalBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("#cb4b16")),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("#268bd2")),
+		win:          lipgloss.NewStyle().Foreground(lipgloss.Color("#dc322f")),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#859900")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#6c71c4")),
+		},
+	},
+	"high-contrast": {
+		border:       lipgloss.Color("15"),
+		cursor:       lipgloss.Color("11"),
+		cursorBorder: lipgloss.ThickBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Underline(true),
+		win:          lipgloss.NewStyle().Reverse(true),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Italic(true),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Bold(true).Underline(true),
+		},
+	},
+	// colorblind uses the Okabe-Ito palette and also tells X and O apart by
+	// weight, so the markers never rely on hue alone.
+	"colorblind": {
+		border:       lipgloss.Color("#999999"),
+		cursor:       lipgloss.Color("#F0E442"),
+		cursorBorder: lipgloss.ThickBorder(),
+		x:            lipgloss.NewStyle().Foreground(lipgloss.Color("#E69F00")).Bold(true),
+		o:            lipgloss.NewStyle().Foreground(lipgloss.Color("#0072B2")).Faint(true),
+		win:          lipgloss.NewStyle().Reverse(true),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#009E73")).Italic(true),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#CC79A7")).Underline(true),
+		},
+	},
+	// monochrome uses no colour at all; the cursor and winning line are
+	// shown through border shape and reverse video instead.
+	"monochrome": {
+		border:       lipgloss.NoColor{},
+		cursor:       lipgloss.NoColor{},
+		cursorBorder: lipgloss.DoubleBorder(),
+		x:            lipgloss.NewStyle().Bold(true),
+		o:            lipgloss.NewStyle(),
+		win:          lipgloss.NewStyle().Reverse(true),
+		extra: []lipgloss.Style{
+			lipgloss.NewStyle().Italic(true),
+			lipgloss.NewStyle().Underline(true),
+		},
+	},
+}
+
+// themeByName looks up a theme, defaulting to "default" for an empty name.
+func themeByName(name string) (theme, error) {
+	if name == "" {
+		name = "default"
+	}
+	t, ok := themes[name]
+	if !ok {
+		return theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames, ", "))
+	}
+	t.name = name
+	return t, nil
+}
+
+// nextThemeName returns the theme that follows name in themeNames.
+func nextThemeName(name string) string {
+	for i, n := range themeNames {
+		if n == name {
+			return themeNames[(i+1)%len(themeNames)]
+		}
+	}
+	return themeNames[0]
+}
+
+// markerStyle returns the style for a cell holding the given marker. In
+// numerical tic-tac-toe the odd numbers take player 1's colour and the even
+// numbers player 2's.
+func (t theme) markerStyle(marker string) lipgloss.Style {
+	switch marker {
+	case "X", "1", "3", "5", "7", "9":
+		return t.x
+	case "O", "2", "4", "6", "8":
+		return t.o
+	case markers[2]:
+		return t.extra[0]
+	case markers[3]:
+		return t.extra[1]
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// promptStyle returns the style of the focused text input prompt.
+func (t theme) promptStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.cursor)
+}
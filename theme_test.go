@@ -0,0 +1,66 @@
+// theme_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestThemeByName checks that every listed theme exists and unknown names fail.
+func TestThemeByName(t *testing.T) {
+	for _, name := range themeNames {
+		th, err := themeByName(name)
+		if err != nil {
+			t.Errorf("themeByName(%q) error = %v", name, err)
+		}
+		if th.name != name {
+			t.Errorf("themeByName(%q).name = %q", name, th.name)
+		}
+	}
+
+	if th, err := themeByName(""); err != nil || th.name != "default" {
+		t.Errorf("Expected empty name to select the default theme, got %q (%v)", th.name, err)
+	}
+	if _, err := themeByName("neon"); err == nil {
+		t.Error("Expected an error for an unknown theme, got nil")
+	}
+}
+
+// TestUpdateThemeCycle checks that the theme key cycles themes and keeps the
+// choice across a new session.
+func TestUpdateThemeCycle(t *testing.T) {
+	m := playingModel(config{})
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updatedModel.(model)
+	if m.theme.name != themeNames[1] {
+		t.Errorf("Expected theme %q after pressing 't', got %q", themeNames[1], m.theme.name)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(model)
+	if m.theme.name != themeNames[1] {
+		t.Errorf("Expected theme %q to survive ctrl+r, got %q", themeNames[1], m.theme.name)
+	}
+
+	if got := nextThemeName(themeNames[len(themeNames)-1]); got != themeNames[0] {
+		t.Errorf("Expected theme cycle to wrap to %q, got %q", themeNames[0], got)
+	}
+}
+
+// TestUpdateThemeNoColor checks that the theme key is disabled and left out
+// of the help under NO_COLOR, so the board stays monochrome.
+func TestUpdateThemeNoColor(t *testing.T) {
+	m := playingModel(config{Theme: "monochrome", noColor: true})
+	m.help.ShowAll = true
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updatedModel.(model)
+	if m.theme.name != "monochrome" {
+		t.Errorf("Expected the theme to stay monochrome, got %q", m.theme.name)
+	}
+	if contains(m.View(), "next theme") {
+		t.Errorf("Help should not list the theme key under NO_COLOR")
+	}
+}
@@ -0,0 +1,56 @@
+// torus.go
+package main
+
+// torusDefaults sizes the two-player torus board: 4x4 with four in a row,
+// so every row, column and wrapped diagonal is a line.
+var torusDefaults = gridConfig{Cols: 4, Rows: 4, Connect: 4}
+
+// newTorusGrid returns an empty width×height grid whose lines wrap round
+// the edges.
+func newTorusGrid(width, height, winLength int) grid {
+	g := newGrid(width, height, winLength)
+	g.torus = true
+	g.lines = gridLines(width, height, winLength, true)
+	return g
+}
+
+// wraps reports whether the board wraps round at the edges, for both lines
+// and the cursor. Only the classic board has the option.
+func (m model) wraps() bool {
+	return m.torus && m.mode == modeClassic
+}
+
+// classicGrid reports whether the classic game is played on the grid rather
+// than the 3x3 board: with more than two players, on a torus, or with
+// blocked cells.
+func (m model) classicGrid() bool {
+	return m.mode == modeClassic && (m.multiplayer() || m.wraps() || m.hasObstacles())
+}
+
+// newClassicGrid returns an empty classic board for the given number of
+// players, sized from the config. A line on a torus can be no longer than
+// the board is wide or high, or it would come back round onto itself.
+func newClassicGrid(players int, torus bool, gc gridConfig) grid {
+	if !torus {
+		gc = gc.withDefaults(multiplayerDefaults(players).sizedFor(gc))
+		return newGrid(gc.Cols, gc.Rows, gc.Connect)
+	}
+	if players > minPlayers {
+		gc = gc.withDefaults(multiplayerDefaults(players))
+	}
+	gc = gc.withDefaults(torusDefaults)
+	return newTorusGrid(gc.Cols, gc.Rows, min(gc.Connect, gc.Cols, gc.Rows))
+}
+
+// moveCursor moves the cursor by (dx, dy), stopping at the edges of the
+// board or, on a torus, coming back in on the other side.
+func (m *model) moveCursor(dx, dy int) {
+	cols, rows := m.boardSize()
+	x, y := m.cursorX+dx, m.cursorY+dy
+	if m.wraps() {
+		x, y = wrap(x, cols), wrap(y, rows)
+	}
+	if x >= 0 && x < cols && y >= 0 && y < rows {
+		m.cursorX, m.cursorY = x, y
+	}
+}
@@ -0,0 +1,101 @@
+// torus_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestGridLines checks the number of lines generated for each topology.
+func TestGridLines(t *testing.T) {
+	testCases := map[string]struct {
+		width, height, length int
+		torus                 bool
+		want                  int
+	}{
+		"Classic board":      {3, 3, 3, false, 8},
+		"Flat 4x4, 3 in row": {4, 4, 3, false, 24},
+		"Torus 3x3":          {3, 3, 3, true, 12},
+		"Torus 4x4":          {4, 4, 4, true, 16},
+		"Torus 5x5, 4 a row": {5, 5, 4, true, 100},
+		"Torus 5x4, 4 a row": {5, 4, 4, true, 65},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := len(gridLines(tc.width, tc.height, tc.length, tc.torus)); got != tc.want {
+				t.Errorf("gridLines() made %d lines, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestTorusWrappingWin checks that a diagonal running off the right edge
+// and back in on the left wins, and is highlighted cell by cell.
+func TestTorusWrappingWin(t *testing.T) {
+	m := playingModel(config{Torus: true})
+	if m.grid.width != 4 || m.grid.height != 4 || m.grid.winLength != 4 {
+		t.Fatalf("Expected a 4x4 torus with four in a row, got %dx%d/%d", m.grid.width, m.grid.height, m.grid.winLength)
+	}
+
+	moves := [][2]int{{1, 0}, {0, 0}, {2, 1}, {0, 1}, {3, 2}, {0, 2}, {0, 3}}
+	for _, mv := range moves {
+		m = placeAt(m, mv[0], mv[1])
+	}
+	if m.winner != "X" {
+		t.Fatalf("Expected X to win with a wrapped diagonal, got %q", m.winner)
+	}
+	for _, mv := range [][2]int{{1, 0}, {2, 1}, {3, 2}, {0, 3}} {
+		if !m.isWinningCell(mv[0], mv[1]) {
+			t.Errorf("Expected (%d, %d) to be highlighted", mv[0], mv[1])
+		}
+	}
+	if m.isWinningCell(0, 0) {
+		t.Errorf("Expected O's cell not to be highlighted")
+	}
+}
+
+// TestTorusCursorWraps checks that the cursor comes back in on the other
+// side of a torus and stops at the edges otherwise.
+func TestTorusCursorWraps(t *testing.T) {
+	m := playingModel(config{Torus: true})
+	press := func(k tea.KeyType) {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updatedModel.(model)
+	}
+
+	press(tea.KeyLeft)
+	press(tea.KeyUp)
+	if m.cursorX != 3 || m.cursorY != 3 {
+		t.Errorf("Expected the cursor to wrap to (3, 3), got (%d, %d)", m.cursorX, m.cursorY)
+	}
+	press(tea.KeyRight)
+	if m.cursorX != 0 {
+		t.Errorf("Expected the cursor to wrap to column 0, got %d", m.cursorX)
+	}
+
+	m = playingModel(config{})
+	press(tea.KeyLeft)
+	if m.cursorX != 0 {
+		t.Errorf("Expected the cursor to stop at the edge of a flat board, got %d", m.cursorX)
+	}
+}
+
+// TestTorusToggle checks that the wrap key switches the board before the
+// first move only.
+func TestTorusToggle(t *testing.T) {
+	m := playingModel(config{})
+	wrapKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}
+
+	updatedModel, _ := m.Update(wrapKey)
+	m = updatedModel.(model)
+	if !m.wraps() || !m.grid.torus || !contains(m.View(), "(Torus)") {
+		t.Fatalf("Expected the wrap key to start a torus game")
+	}
+
+	m = placeAt(m, 0, 0)
+	updatedModel, _ = m.Update(wrapKey)
+	if !updatedModel.(model).wraps() {
+		t.Errorf("Expected the board not to change mid-game")
+	}
+}
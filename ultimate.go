@@ -0,0 +1,167 @@
+// ultimate.go
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ultimateBoard holds an Ultimate Tic-Tac-Toe game: nine small boards laid
+// out as a 3x3 board of boards. Winning a small board claims that square of
+// the big board, and three claimed squares in a row win the game.
+type ultimateBoard struct {
+	boards [9][3][3]string // Small boards, numbered left to right, top to bottom
+	owners [3][3]string    // "X" or "O" once a small board is won, "-" once drawn, else " "
+	active int             // The small board the next move must go in, or -1 for any
+}
+
+// newUltimateBoard returns an empty board of boards where any board may be played.
+func newUltimateBoard() ultimateBoard {
+	var u ultimateBoard
+	for b := range u.boards {
+		u.boards[b] = [3][3]string{{" ", " ", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	}
+	u.owners = [3][3]string{{" ", " ", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	u.active = -1
+	return u
+}
+
+// subBoard maps a position on the 9x9 grid to a small board and the cell
+// within it.
+func subBoard(x, y int) (b, cx, cy int) {
+	return (y/3)*3 + x/3, x % 3, y % 3
+}
+
+// playable reports whether small board b may receive the next move.
+func (u ultimateBoard) playable(b int) bool {
+	return u.owners[b/3][b%3] == " " && (u.active < 0 || u.active == b)
+}
+
+// placeUltimate plays a move at the cursor on the 9x9 grid.
+func (m *model) placeUltimate() moveResult {
+	u := &m.ultimate
+	b, cx, cy := subBoard(m.cursorX, m.cursorY)
+	if !u.playable(b) || u.boards[b][cy][cx] != " " {
+		return moveIllegal
+	}
+
+	u.boards[b][cy][cx] = m.player
+	if won, _ := checkWinner(u.boards[b], m.player); won {
+		u.owners[b/3][b%3] = m.player
+	} else if checkDraw(u.boards[b]) {
+		u.owners[b/3][b%3] = "-"
+	}
+
+	// The cell just played picks the opponent's board, unless that board
+	// is already decided, in which case they may play anywhere.
+	u.active = cy*3 + cx
+	if u.owners[cy][cx] != " " {
+		u.active = -1
+	}
+
+	if won, cells := checkWinner(u.owners, m.player); won {
+		m.winningCells = cells
+		return moveWon
+	}
+	if checkDraw(u.owners) {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// ultimateCellWidths lists the cell widths tried when drawing the 9x9 grid,
+// largest first.
+var ultimateCellWidths = []int{5, 3}
+
+// viewUltimateBoard draws the board of boards. It returns the size needed
+// and false when the grid does not fit in the terminal.
+func viewUltimateBoard(m model, reservedLines int) (view string, needWidth, needHeight int, ok bool) {
+	cellWidth := ultimateCellWidths[0]
+	if m.width > 0 && m.height > 0 {
+		ok = false
+		for _, w := range ultimateCellWidths {
+			cellWidth = w
+			needWidth, needHeight = 3*(3*w+2), 3*5+reservedLines
+			if needWidth <= m.width && needHeight <= m.height {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return "", needWidth, needHeight, false
+		}
+	}
+
+	over := m.winner != "" || m.isDraw
+	var rows []string
+	for by := 0; by < 3; by++ {
+		var boards []string
+		for bx := 0; bx < 3; bx++ {
+			boards = append(boards, renderSubBoard(m, by*3+bx, cellWidth, over))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, boards...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...), needWidth, needHeight, true
+}
+
+// renderSubBoard draws small board b, outlining it when the current player
+// may play there.
+func renderSubBoard(m model, b, cellWidth int, over bool) string {
+	u := m.ultimate
+	owner := u.owners[b/3][b%3]
+
+	winning := false
+	for _, c := range m.winningCells {
+		if c.y*3+c.x == b {
+			winning = true
+		}
+	}
+
+	var lines []string
+	for cy := 0; cy < 3; cy++ {
+		var line strings.Builder
+		for cx := 0; cx < 3; cx++ {
+			x, y := (b%3)*3+cx, (b/3)*3+cy
+			cell := u.boards[b][cy][cx]
+
+			style := lipgloss.NewStyle()
+			if winning {
+				style = style.Inherit(m.theme.win)
+			}
+			if owner == "-" {
+				style = style.Faint(true)
+			} else if owner != " " && !winning {
+				// A won board is tinted in its owner's colour.
+				style = style.Inherit(m.theme.markerStyle(owner))
+			}
+			style = style.Inherit(m.theme.markerStyle(cell))
+
+			if cell == " " {
+				cell = "·"
+			}
+			pad := strings.Repeat(" ", (cellWidth-3)/2)
+			if m.cursorX == x && m.cursorY == y {
+				bracket := lipgloss.NewStyle().Foreground(m.theme.cursor)
+				line.WriteString(pad + bracket.Render("[") + style.Render(cell) + bracket.Render("]") + pad)
+			} else {
+				line.WriteString(pad + " " + style.Render(cell) + " " + pad)
+			}
+		}
+		lines = append(lines, line.String())
+	}
+
+	box := lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true).BorderForeground(m.theme.border)
+	if !over && u.playable(b) {
+		box = box.Border(m.theme.cursorBorder, true).BorderForeground(m.theme.cursor)
+	}
+	return box.Render(strings.Join(lines, "\n"))
+}
+
+// ultimateHint tells the current player which boards they may play in.
+func ultimateHint(m model) string {
+	if m.ultimate.active < 0 {
+		return "Play in any open board."
+	}
+	return "Play in the highlighted board."
+}
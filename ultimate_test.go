@@ -0,0 +1,112 @@
+// ultimate_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestUltimateSendsOpponent checks that the cell played picks the next board.
+func TestUltimateSendsOpponent(t *testing.T) {
+	m := playingModel(config{Mode: "ultimate"})
+
+	// X plays the top-right cell of the centre board, sending O to board 2.
+	m = placeAt(m, 5, 3)
+	if m.ultimate.boards[4][0][2] != "X" {
+		t.Fatalf("Expected X in board 4 at (2, 0), got %q", m.ultimate.boards[4][0][2])
+	}
+	if m.ultimate.active != 2 {
+		t.Errorf("Expected active board 2, got %d", m.ultimate.active)
+	}
+
+	// O may not play outside board 2.
+	m = placeAt(m, 0, 0)
+	if m.player != "O" || m.ultimate.boards[0][0][0] != " " {
+		t.Errorf("Expected a move outside the active board to be rejected")
+	}
+
+	m = placeAt(m, 6, 0)
+	if m.ultimate.boards[2][0][0] != "O" || m.player != "X" {
+		t.Errorf("Expected O's move in board 2 to be accepted")
+	}
+}
+
+// TestUltimateClaimBoard checks that winning a small board claims it and that
+// being sent to a decided board frees the next move.
+func TestUltimateClaimBoard(t *testing.T) {
+	m := playingModel(config{Mode: "ultimate"})
+	m.ultimate.boards[0] = [3][3]string{{"X", "X", " "}, {"O", "O", " "}, {" ", " ", " "}}
+	m.ultimate.active = 0
+
+	// X completes the top row of board 0 with the top-right cell, which
+	// would send O to board 2.
+	m = placeAt(m, 2, 0)
+	if m.ultimate.owners[0][0] != "X" {
+		t.Errorf("Expected board 0 to be claimed by X, got %q", m.ultimate.owners[0][0])
+	}
+	if m.ultimate.active != 2 {
+		t.Errorf("Expected active board 2, got %d", m.ultimate.active)
+	}
+
+	// O plays the top-left cell of board 2, which points back at the claimed
+	// board 0, so X may play anywhere.
+	m = placeAt(m, 6, 0)
+	if m.ultimate.active != -1 {
+		t.Errorf("Expected any board to be playable, got active %d", m.ultimate.active)
+	}
+	if m.ultimate.playable(0) {
+		t.Errorf("Expected claimed board 0 to be closed")
+	}
+}
+
+// TestUltimateWin checks that three claimed boards in a row win the game.
+func TestUltimateWin(t *testing.T) {
+	m := playingModel(config{Mode: "ultimate"})
+	m.seats[0].name = "P1"
+	m.ultimate.owners = [3][3]string{{"X", "X", " "}, {"O", "O", " "}, {" ", " ", " "}}
+	m.ultimate.boards[2] = [3][3]string{{"X", "X", " "}, {" ", " ", " "}, {" ", " ", " "}}
+	m.ultimate.active = 2
+
+	m = placeAt(m, 8, 0)
+	if m.winner != "X" || m.seats[0].score != 1 {
+		t.Fatalf("Expected X to win the game, got winner %q score %d", m.winner, m.seats[0].score)
+	}
+	if len(m.winningCells) != 3 {
+		t.Errorf("Expected 3 winning boards, got %d", len(m.winningCells))
+	}
+	if !contains(m.View(), "P1 wins!") {
+		t.Errorf("View does not announce the winner")
+	}
+}
+
+// TestUpdateModeKey checks that the mode key switches modes before the first move only.
+func TestUpdateModeKey(t *testing.T) {
+	m := playingModel(config{})
+	press := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}}
+
+	updatedModel, _ := m.Update(press)
+	m = updatedModel.(model)
+	if m.mode != modeUltimate {
+		t.Fatalf("Expected ultimate mode, got %s", m.mode)
+	}
+	if !contains(m.View(), "Ultimate Tic-Tac-Toe") {
+		t.Errorf("View does not show the ultimate title")
+	}
+
+	// The cursor now reaches the far corner of the 9x9 grid.
+	for i := 0; i < 10; i++ {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		m = updatedModel.(model)
+	}
+	if m.cursorX != 8 {
+		t.Errorf("Expected cursorX to stop at 8, got %d", m.cursorX)
+	}
+
+	m = placeAt(m, 0, 0)
+	updatedModel, _ = m.Update(press)
+	m = updatedModel.(model)
+	if m.mode != modeUltimate {
+		t.Errorf("Expected the mode to stay ultimate after a move, got %s", m.mode)
+	}
+}
@@ -0,0 +1,29 @@
+// wild.go
+package main
+
+import "fmt"
+
+// placeWild places the armed marker at the cursor. A line of either marker
+// wins for the player who completed it, whichever marker they placed.
+func (m *model) placeWild() moveResult {
+	if m.board[m.cursorY][m.cursorX] != " " {
+		return moveIllegal
+	}
+	m.board[m.cursorY][m.cursorX] = m.armed
+	for _, marker := range []string{"X", "O"} {
+		if won, cells := checkWinner(m.board, marker); won {
+			m.winningCells = cells
+			return moveWon
+		}
+	}
+	if checkDraw(m.board) {
+		return moveDrawn
+	}
+	return moveMade
+}
+
+// markerHint tells the current player which marker they are about to place.
+func markerHint(m model) string {
+	armed := m.theme.markerStyle(m.armed).Bold(true).Render(m.armed)
+	return fmt.Sprintf("Placing %s (press %s to switch)", armed, m.keys.Marker.Help().Key)
+}
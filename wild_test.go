@@ -0,0 +1,56 @@
+// wild_test.go
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestWildMarkerKey checks that the marker key switches the armed marker.
+func TestWildMarkerKey(t *testing.T) {
+	m := playingModel(config{Mode: "wild"})
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updatedModel.(model)
+	if m.armed != "O" {
+		t.Fatalf("Expected O to be armed, got %q", m.armed)
+	}
+	m = placeAt(m, 1, 1)
+	if m.board[1][1] != "O" || m.player != "O" {
+		t.Errorf("Expected player X to place O and pass the turn")
+	}
+	if m.armed != "X" {
+		t.Errorf("Expected the next player to start with X armed, got %q", m.armed)
+	}
+
+	// The marker key does nothing outside wild mode.
+	m = playingModel(config{})
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if updatedModel.(model).armed != "X" {
+		t.Errorf("Expected the marker key to be disabled in classic mode")
+	}
+}
+
+// TestWildWinIsMover checks that completing a line of the other player's
+// usual marker still wins for the mover.
+func TestWildWinIsMover(t *testing.T) {
+	m := playingModel(config{Mode: "wild"})
+	m.board = [3][3]string{
+		{"X", "X", " "},
+		{" ", " ", " "},
+		{" ", " ", " "},
+	}
+	m.player = "O"
+
+	m = placeAt(m, 2, 0)
+	if m.winner != "O" || m.seats[1].score != 1 {
+		t.Fatalf("Expected O to win with a line of X, got winner %q", m.winner)
+	}
+	if len(m.winningCells) != 3 {
+		t.Errorf("Expected the winning line to be highlighted")
+	}
+	if !contains(m.View(), "P2 wins!") {
+		t.Errorf("View does not report P2's win")
+	}
+}